@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var amendCommit string = ""
+
+var amendCmd = &cobra.Command{
+	Use:   "amend [flags] [-- git commit options]",
+	Short: "Regenerate the message of HEAD and amend it",
+	Long:  `Regenerate the message of HEAD from its diff plus any newly staged changes, then run git commit --amend`,
+	Args:  commitArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		err := internal.AmendCommit(ctx, amendCommit, args)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	amendCmd.Flags().StringVarP(&amendCommit, "commit", "c", "", "commit message")
+	rootCmd.AddCommand(amendCmd)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the response cache",
+	Long:  `Manage the on-disk cache of generated responses`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached response",
+	Long:  `Remove every cached response`,
+	Run: func(cmd *cobra.Command, args []string) {
+		responseCache, err := llm.NewResponseCache(config.Get())
+		if err != nil {
+			exitWithError(err)
+		}
+		if err := responseCache.Clear(); err != nil {
+			exitWithError(err)
+		}
+		fmt.Println(i18n.T("cache.cleared"))
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the size of the response cache",
+	Long:  `Show the size of the response cache`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.Get()
+		responseCache, err := llm.NewResponseCache(cfg)
+		if err != nil {
+			exitWithError(err)
+		}
+		stats, err := responseCache.Stats()
+		if err != nil {
+			exitWithError(err)
+		}
+
+		fmt.Println(i18n.T("cache.directory", stats.Dir))
+		fmt.Println(i18n.T("cache.enabled", cfg.Cache.Enabled))
+		fmt.Println(i18n.T("cache.entries", stats.Entries, stats.Expired))
+		fmt.Println(i18n.T("cache.size", humanize.Bytes(uint64(stats.Size)), cfg.Cache.MaxSizeMB))
+		fmt.Println(i18n.T("cache.ttl", cfg.Cache.TTL))
+		if stats.Entries > 0 {
+			fmt.Println(i18n.T("cache.oldest", humanize.Time(stats.Oldest)))
+			fmt.Println(i18n.T("cache.newest", humanize.Time(stats.Newest)))
+		}
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -1,9 +1,7 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
+	"github.com/Beriholic/geminic/internal"
 	"github.com/Beriholic/geminic/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -11,15 +9,83 @@ import (
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Set the config file",
-	Long:  `Set the config file`,
+	Long: `Set the config file
+
+Without a subcommand the settings are asked for interactively`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := config.Create(); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			exitWithError(err)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Long:  `Print the value of a setting, defaults included`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.GetSetting(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>...",
+	Short: "Validate and save a setting",
+	Long: `Validate and save a setting, leaving the rest of the config file as it is
+
+List settings take one value per item, e.g. geminic config set commit_args -- -S --signoff`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.SetSetting(args[0], args[1:]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every setting with the key masked",
+	Long:  `List every setting with the key masked`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.ListSettings(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in your editor",
+	Long:  `Open the config file in the editor git uses and validate it afterwards`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.EditSettings(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file",
+	Long:  `Check the config file and report every invalid setting`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.ValidateSettings(); err != nil {
+			exitWithError(err)
 		}
 	},
 }
 
 func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
 	rootCmd.AddCommand(configCmd)
 }
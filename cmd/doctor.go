@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check git, the config and the provider",
+	Long: `Check git, the repository, the config file, the key, the provider endpoint
+and the configured model, and print how to fix what is wrong`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.Doctor(cmd.Context()); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
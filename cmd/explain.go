@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var explainMaxCount int = 10
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <rev|range|path>",
+	Short: "Explain what a commit, a range or a file's history does",
+	Long: `Explain in plain language what a commit, a range of commits (e.g. v1.0..v1.1)
+or the history of a single file changed: its intent, the affected areas and the risky parts.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		err := internal.ExplainHistory(ctx, args[0], explainMaxCount)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	explainCmd.Flags().IntVarP(&explainMaxCount, "max-count", "n", 10, "maximum number of commits to explain for a range or path")
+	rootCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var historyOptions = internal.HistoryOptions{}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [query] [-- git commit options]",
+	Short: "Browse and reuse previously generated messages",
+	Long: `Browse and search every generated commit message candidate, and commit the staged changes with one of them.
+Options after -- are passed to git commit like for the commit command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, historyOptions.CommitArgs = args[:dash], args[dash:]
+		}
+		historyOptions.Query = strings.Join(args, " ")
+		if err := internal.BrowseHistory(historyOptions); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	historyCmd.Flags().BoolVarP(&historyOptions.AllRepos, "all", "a", false, "include every repository, not only the current one")
+	historyCmd.Flags().BoolVarP(&historyOptions.Staged, "staged", "s", false, "only show candidates generated for the staged changes")
+	historyCmd.Flags().IntVarP(&historyOptions.Limit, "limit", "n", 50, "maximum number of entries")
+	historyCmd.Flags().BoolVarP(&historyOptions.Print, "print", "p", false, "print the entries instead of picking one")
+	rootCmd.AddCommand(historyCmd)
+}
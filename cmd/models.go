@@ -1,9 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/Beriholic/geminic/internal"
 	"github.com/spf13/cobra"
 )
@@ -16,8 +13,7 @@ var modelsCmd = &cobra.Command{
 		ctx := cmd.Context()
 		err := internal.UpdateModelSelect(ctx)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
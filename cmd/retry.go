@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var retryCmd = &cobra.Command{
+	Use:   "retry [-- git commit options]",
+	Short: "Commit again with the message of the last failed commit",
+	Long: `When git commit fails, e.g. in a pre-commit or commit-msg hook, the generated message
+is saved to .git/GEMINIC_MSG with the git commit options it was run with. Fix the problem
+and run retry to commit with it again without asking the model. Options after -- are
+added to the saved ones.`,
+	Args: commitArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := internal.RetryCommit(args)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(retryCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var reviewFormat string = internal.ReviewFormatText
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review the staged changes before committing",
+	Long:  `Send the staged diff to the model for a quick review and list its findings as text, JSON or SARIF`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		err := internal.ReviewChanges(ctx, reviewFormat)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	reviewCmd.Flags().StringVarP(&reviewFormat, "format", "f", internal.ReviewFormatText, "output format: text, json or sarif")
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var rewordCmd = &cobra.Command{
+	Use:   "reword <range>",
+	Short: "Regenerate the messages of a range of commits",
+	Long: `Regenerate the messages of each commit in a range (e.g. main..HEAD) and rewrite them through a non-interactive rebase.
+A single revision rewords only that commit. Commits already on a protected upstream are refused.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		err := internal.RewordCommits(ctx, args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rewordCmd)
+}
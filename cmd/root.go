@@ -5,25 +5,57 @@ import (
 	"os"
 
 	"github.com/Beriholic/geminic/internal"
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
 	"github.com/spf13/cobra"
 )
 
-var userCommit string = ""
+var (
+	userCommit  string = ""
+	review      bool   = false
+	stageAll    bool   = false
+	interactive bool   = false
+	offline     bool   = false
+	configPath  string = ""
+)
 
 func init() {
+	cobra.OnInitialize(func() {
+		config.SetPath(configPath)
+	})
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "config file (default $XDG_CONFIG_HOME/geminic/config.toml)")
 	rootCmd.Flags().StringVarP(&userCommit, "commit", "c", "", "commit message")
+	rootCmd.Flags().BoolVarP(&stageAll, "all", "a", false, "stage modified and deleted tracked files first, like git commit -a")
+	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "select the files to stage before generating")
+	rootCmd.Flags().BoolVar(&offline, "offline", false, "write the message from the changed files and symbols without a model")
+	rootCmd.Flags().BoolVar(&review, "review", false, "review the staged changes first and block on high severity findings (default from config)")
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "geminic",
+	Use:   "geminic [flags] [-- git commit options]",
 	Short: "Using Gemini to Write Git Commits ",
-	Long:  `Using Gemini to Write Git Commits `,
+	Long: `Using Gemini to Write Git Commits
+
+Everything after -- is passed to git commit, e.g. geminic -- -S --signoff`,
+	Args: commitArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
-		err := internal.GeneratorCommit(ctx, userCommit)
+		if !cmd.Flags().Changed("review") {
+			review = config.Get().Review
+		}
+		if offline {
+			config.Get().ModelProvider = model_provider.Heuristic
+		}
+		err := internal.GeneratorCommit(ctx, internal.CommitOptions{
+			UserCommit:  userCommit,
+			Review:      review,
+			All:         stageAll,
+			Interactive: interactive,
+			CommitArgs:  args,
+		})
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -34,3 +66,20 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// commitArgs only accepts arguments given after --, which are passed to git
+// commit as they are.
+func commitArgs(cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash != 0 && len(args) > 0 {
+		return fmt.Errorf("unexpected argument %q, pass git commit options after --", args[0])
+	}
+	return nil
+}
+
+func exitWithError(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	if hint := llm.Hint(err); hint != "" {
+		fmt.Fprintf(os.Stderr, "hint: %s\n", hint)
+	}
+	os.Exit(1)
+}
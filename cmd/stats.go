@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/Beriholic/geminic/internal"
+	"github.com/spf13/cobra"
+)
+
+var statsDays int = 30
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize token usage, cost and acceptance rate",
+	Long:  `Summarize requests, tokens, estimated cost and how often generated messages were accepted, per model, per repo and per day`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := internal.ShowStats(statsDays); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	statsCmd.Flags().IntVarP(&statsDays, "days", "d", 30, "only include the last N days, 0 for everything")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		same bool
+	}{
+		{"same parts", []string{"Gemini", "flash", "prompt"}, []string{"Gemini", "flash", "prompt"}, true},
+		{"different model", []string{"Gemini", "flash", "prompt"}, []string{"Gemini", "pro", "prompt"}, false},
+		{"parts are not concatenated", []string{"ab", "c"}, []string{"a", "bc"}, false},
+		{"empty part counts", []string{"a", ""}, []string{"a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.a...) == Key(tt.b...); got != tt.same {
+				t.Errorf("Key(%q) == Key(%q) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestGetPut(t *testing.T) {
+	c := New(t.TempDir(), time.Hour, 0)
+
+	if _, ok := c.Get(Key("missing")); ok {
+		t.Fatal("Get() found an entry that was never put")
+	}
+	if err := c.Put(Key("a"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	data, ok := c.Get(Key("a"))
+	if !ok || string(data) != "value" {
+		t.Fatalf("Get() = %q, %v, want %q, true", data, ok, "value")
+	}
+}
+
+func TestTTL(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, time.Hour, 0)
+
+	if err := c.Put(Key("old"), []byte("old")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(Key("new"), []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	age(t, filepath.Join(dir, Key("old")), 2*time.Hour)
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 || stats.Expired != 1 {
+		t.Errorf("Stats() = %d entries, %d expired, want 2 and 1", stats.Entries, stats.Expired)
+	}
+
+	if _, ok := c.Get(Key("old")); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	if _, err := os.Stat(filepath.Join(dir, Key("old"))); !os.IsNotExist(err) {
+		t.Error("Get() kept the expired entry on disk")
+	}
+	if _, ok := c.Get(Key("new")); !ok {
+		t.Error("Get() dropped an entry that has not expired")
+	}
+}
+
+func TestSizeCap(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, 0, 25)
+
+	for i, key := range []string{"first", "second", "third"} {
+		if err := c.Put(Key(key), []byte(strings.Repeat("x", 10))); err != nil {
+			t.Fatal(err)
+		}
+		age(t, filepath.Join(dir, Key(key)), time.Duration(3-i)*time.Minute)
+	}
+
+	// The third Put pruned the oldest entry to fit in 25 bytes.
+	if _, ok := c.Get(Key("first")); ok {
+		t.Error("the oldest entry was kept over the size cap")
+	}
+	for _, key := range []string{"second", "third"} {
+		if _, ok := c.Get(Key(key)); !ok {
+			t.Errorf("entry %q was pruned", key)
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "responses")
+	c := New(dir, 0, 0)
+	if err := c.Put(Key("a"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 0 {
+		t.Errorf("Stats().Entries = %d after Clear, want 0", stats.Entries)
+	}
+}
+
+func age(t *testing.T, path string, by time.Duration) {
+	t.Helper()
+	at := time.Now().Add(-by)
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"sync"
 
+	"github.com/Beriholic/geminic/internal/i18n"
 	"github.com/Beriholic/geminic/internal/model"
 	"github.com/Beriholic/geminic/internal/model/model_provider"
 	"github.com/charmbracelet/huh"
@@ -31,7 +33,8 @@ func Get() *model.Config {
 		config, err = load()
 	})
 	if err != nil {
-		fmt.Printf("failed to load config: %v", err)
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
 	}
 	return config
 }
@@ -43,29 +46,35 @@ func Create() error {
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
-				Title("What is your Gemini API key?").
+				Title(i18n.T("config.key")).
 				Value(&config.Key),
 			huh.NewInput().
-				Title("Which model do you want to use?").
+				Title(i18n.T("config.model")).
 				Value(&config.Model),
 			huh.NewSelect[bool]().
-				Title("Do you want to enable emoji?").
+				Title(i18n.T("config.emoji")).
 				Options(
-					huh.NewOption("Yes", true),
-					huh.NewOption("No", false),
+					huh.NewOption(i18n.T("action.yes"), true),
+					huh.NewOption(i18n.T("action.no"), false),
 				).
 				Value(&config.Emoji),
 			huh.NewInput().
-				Title("Custom backend connection (leave blank to disable)").
+				Title(i18n.T("config.custom_url")).
 				Value(&config.CustomURL),
 			huh.NewInput().
-				Title("i18n").
+				Title(i18n.T("config.i18n")).
+				Validate(i18n.Validate).
 				Value(&config.I18n),
 			huh.NewSelect[string]().
-				Title("Model Provider").
+				Title(i18n.T("config.language")).
+				Options(languageOptions()...).
+				Value(&config.Language),
+			huh.NewSelect[string]().
+				Title(i18n.T("config.provider")).
 				Options(
 					huh.NewOption(model_provider.Gemini, model_provider.Gemini),
 					huh.NewOption(model_provider.OpenAI, model_provider.OpenAI),
+					huh.NewOption(i18n.T("config.offline", model_provider.Heuristic), model_provider.Heuristic),
 				).
 				Value(&config.ModelProvider),
 		).WithTheme(huh.ThemeBase()),
@@ -78,15 +87,29 @@ func Create() error {
 	return config.Save()
 }
 
+func languageOptions() []huh.Option[string] {
+	options := []huh.Option[string]{huh.NewOption(i18n.T("config.language_auto"), "")}
+	for _, lang := range i18n.Languages() {
+		options = append(options, huh.NewOption(lang, lang))
+	}
+	return options
+}
+
 func load() (*model.Config, error) {
 	var config model.Config
 	err := config.Load()
 	if err != nil {
 		return nil, err
 	}
+	i18n.SetLanguage(config.Language)
 	return &config, nil
 }
 
+// SetPath makes geminic read and write the config file at path.
+func SetPath(path string) {
+	model.SetConfigPath(path)
+}
+
 func SetModel(model string) error {
 	config := Get()
 	config.Model = model
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/deps"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+)
+
+// submoduleMode is the mode git gives the commit a submodule points to.
+const submoduleMode = "160000"
+
+// dependencyBumps reads the version bumps out of the manifests and submodules
+// changed between base and rev, see describeChanges. only reports whether
+// changes are nothing but those bumps and their lock files.
+func dependencyBumps(base, rev string, changes []dto.FileChange) (bumps []deps.Bump, only bool) {
+	gitService := service.GetGitService()
+	if base == "" {
+		return nil, false
+	}
+
+	only = true
+	for _, change := range changes {
+		switch {
+		case change.Status == "M" && change.OldMode == submoduleMode && change.NewMode == submoduleMode:
+			from, err := gitService.ObjectID(base, change.Path)
+			if err != nil {
+				return nil, false
+			}
+			to, err := gitService.ObjectID(rev, change.Path)
+			if err != nil {
+				return nil, false
+			}
+			bumps = append(bumps, deps.Bump{Name: change.Path, From: from[:7], To: to[:7], File: change.Path})
+		case change.Status == "M" && deps.IsManifest(change.Path):
+			old, err := gitService.FileAt(base, change.Path)
+			if err != nil {
+				return nil, false
+			}
+			new, err := gitService.FileAt(rev, change.Path)
+			if err != nil {
+				return nil, false
+			}
+			manifestBumps, manifestOnly, err := deps.ManifestBumps(change.Path, old, new)
+			if err != nil {
+				only = false
+				continue
+			}
+			bumps = append(bumps, manifestBumps...)
+			only = only && manifestOnly
+		case deps.IsLockFile(change.Path):
+		default:
+			only = false
+		}
+	}
+
+	return bumps, only && len(bumps) > 0
+}
+
+// commitDependencyBumps commits changes that only bump dependencies with a
+// message built from the bumps, without asking the model.
+func commitDependencyBumps(bumps []deps.Bump, commitArgs []string) error {
+	commit := dto.GitCommit{Typ: "build", Scope: "deps", Msg: deps.Subject(bumps)}
+	if config.Get().Emoji {
+		commit.Emoji = ":package:"
+	}
+	message := commit.String()
+	if body := deps.Body(bumps); body != "" {
+		message += "\n\n" + body
+	}
+
+	fmt.Println(ui.FormatText(i18n.T("commit.dependency_update"), message))
+	message, err := confirmMessage(message)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		fmt.Println(i18n.T("commit.cancelled"))
+		return nil
+	}
+
+	return commitMessage(message, commitArgs, nil)
+}
@@ -0,0 +1,159 @@
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Bump is a dependency whose version changed.
+type Bump struct {
+	Name string
+	From string
+	To   string
+	// File is the manifest or submodule path the bump was read from.
+	File string
+}
+
+func (b Bump) String() string {
+	return fmt.Sprintf("bump %s from %s to %s", b.Name, b.From, b.To)
+}
+
+// lockFiles only change along with their manifest and are never parsed.
+var lockFiles = []string{"go.sum", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml"}
+
+func IsLockFile(file string) bool {
+	return slices.Contains(lockFiles, path.Base(file))
+}
+
+func IsManifest(file string) bool {
+	switch path.Base(file) {
+	case "go.mod", "package.json":
+		return true
+	}
+	return false
+}
+
+// ManifestBumps compares two versions of a go.mod or package.json. only is
+// false when anything else changed as well, e.g. a dependency was added or a
+// script edited.
+func ManifestBumps(file string, old, new []byte) (bumps []Bump, only bool, err error) {
+	var oldDeps, newDeps map[string]string
+	var oldRest, newRest string
+
+	switch path.Base(file) {
+	case "go.mod":
+		oldDeps, oldRest = parseGoMod(old)
+		newDeps, newRest = parseGoMod(new)
+	case "package.json":
+		if oldDeps, oldRest, err = parsePackageJSON(old); err != nil {
+			return nil, false, fmt.Errorf("failed to parse %s. %v", file, err)
+		}
+		if newDeps, newRest, err = parsePackageJSON(new); err != nil {
+			return nil, false, fmt.Errorf("failed to parse %s. %v", file, err)
+		}
+	default:
+		return nil, false, fmt.Errorf("%s is not a known manifest", file)
+	}
+
+	only = oldRest == newRest && len(oldDeps) == len(newDeps)
+	for _, name := range slices.Sorted(maps.Keys(newDeps)) {
+		from, ok := oldDeps[name]
+		if !ok {
+			only = false
+			continue
+		}
+		if to := newDeps[name]; from != to {
+			bumps = append(bumps, Bump{Name: name, From: from, To: to, File: file})
+		}
+	}
+
+	return bumps, only && len(bumps) > 0, nil
+}
+
+// parseGoMod returns the required modules of a go.mod and everything else
+// in it, so that two versions can be told apart by more than versions.
+func parseGoMod(src []byte) (map[string]string, string) {
+	deps := make(map[string]string)
+	var rest strings.Builder
+
+	inRequire := false
+	for _, line := range strings.Split(string(src), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire && len(fields) >= 2:
+			deps[fields[0]] = fields[1]
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) >= 3:
+			deps[fields[1]] = fields[2]
+		default:
+			rest.WriteString(strings.Join(fields, " ") + "\n")
+		}
+	}
+
+	return deps, rest.String()
+}
+
+var packageJSONSections = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+func parsePackageJSON(src []byte) (map[string]string, string, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(src, &manifest); err != nil {
+		return nil, "", err
+	}
+
+	deps := make(map[string]string)
+	for _, section := range packageJSONSections {
+		raw, ok := manifest[section]
+		if !ok {
+			continue
+		}
+		var sectionDeps map[string]string
+		if err := json.Unmarshal(raw, &sectionDeps); err != nil {
+			return nil, "", err
+		}
+		for name, version := range sectionDeps {
+			deps[name] = version
+		}
+		delete(manifest, section)
+	}
+
+	// json.Marshal sorts map keys, which makes the rest comparable
+	rest, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, "", err
+	}
+	return deps, string(rest), nil
+}
+
+// Subject describes bumps in a commit subject: the bump itself when there is
+// one, otherwise their count.
+func Subject(bumps []Bump) string {
+	if len(bumps) == 1 {
+		return bumps[0].String()
+	}
+	return fmt.Sprintf("bump %d dependencies", len(bumps))
+}
+
+// Body lists bumps one per line when the subject only counts them.
+func Body(bumps []Bump) string {
+	if len(bumps) == 1 {
+		return ""
+	}
+
+	lines := make([]string, len(bumps))
+	for idx, bump := range bumps {
+		lines[idx] = "- " + bump.String()
+	}
+	return strings.Join(lines, "\n")
+}
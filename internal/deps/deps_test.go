@@ -0,0 +1,162 @@
+package deps
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const goMod = `module example.com/x
+
+go 1.24
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/spf13/viper v1.18.2
+	golang.org/x/text v0.14.0 // indirect
+)
+`
+
+const packageJSON = `{
+  "name": "x",
+  "scripts": {"build": "tsc"},
+  "dependencies": {"react": "^18.2.0"},
+  "devDependencies": {"typescript": "~5.3.0"}
+}`
+
+func TestManifestBumps(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		old     string
+		new     string
+		want    []Bump
+		only    bool
+		wantErr bool
+	}{
+		{
+			name: "go.mod single require",
+			file: "go.mod",
+			old:  goMod,
+			new:  replace(goMod, "cobra v1.8.0", "cobra v1.8.1"),
+			want: []Bump{{Name: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.8.1", File: "go.mod"}},
+			only: true,
+		},
+		{
+			name: "go.mod require block, sorted",
+			file: "tools/go.mod",
+			old:  goMod,
+			new:  replace(replace(goMod, "viper v1.18.2", "viper v1.19.0"), "text v0.14.0", "text v0.15.0"),
+			want: []Bump{
+				{Name: "github.com/spf13/viper", From: "v1.18.2", To: "v1.19.0", File: "tools/go.mod"},
+				{Name: "golang.org/x/text", From: "v0.14.0", To: "v0.15.0", File: "tools/go.mod"},
+			},
+			only: true,
+		},
+		{
+			name: "go.mod go directive changed too",
+			file: "go.mod",
+			old:  goMod,
+			new:  replace(replace(goMod, "cobra v1.8.0", "cobra v1.8.1"), "go 1.24", "go 1.25"),
+			want: []Bump{{Name: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.8.1", File: "go.mod"}},
+		},
+		{
+			name: "go.mod dependency added",
+			file: "go.mod",
+			old:  goMod,
+			new:  replace(goMod, "require github.com/spf13/cobra v1.8.0", "require github.com/spf13/cobra v1.8.0\nrequire github.com/fatih/color v1.16.0"),
+		},
+		{
+			name: "go.mod formatting only",
+			file: "go.mod",
+			old:  goMod,
+			new:  replace(goMod, "\tgithub.com/spf13/viper v1.18.2", "\tgithub.com/spf13/viper   v1.18.2"),
+		},
+		{
+			name: "package.json bump",
+			file: "web/package.json",
+			old:  packageJSON,
+			new:  replace(packageJSON, "~5.3.0", "~5.4.0"),
+			want: []Bump{{Name: "typescript", From: "~5.3.0", To: "~5.4.0", File: "web/package.json"}},
+			only: true,
+		},
+		{
+			name: "package.json script edited too",
+			file: "package.json",
+			old:  packageJSON,
+			new:  replace(replace(packageJSON, "^18.2.0", "^18.3.0"), "tsc", "tsc -b"),
+			want: []Bump{{Name: "react", From: "^18.2.0", To: "^18.3.0", File: "package.json"}},
+		},
+		{
+			name: "package.json dependency removed",
+			file: "package.json",
+			old:  packageJSON,
+			new:  replace(packageJSON, `"dependencies": {"react": "^18.2.0"},`, ""),
+		},
+		{
+			name:    "package.json invalid",
+			file:    "package.json",
+			old:     packageJSON,
+			new:     "{",
+			wantErr: true,
+		},
+		{
+			name:    "unknown manifest",
+			file:    "Cargo.toml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bumps, only, err := ManifestBumps(tt.file, []byte(tt.old), []byte(tt.new))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ManifestBumps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(bumps, tt.want) || only != tt.only {
+				t.Errorf("ManifestBumps() = %+v, %v\nwant %+v, %v", bumps, only, tt.want, tt.only)
+			}
+		})
+	}
+}
+
+func TestSubjectAndBody(t *testing.T) {
+	one := []Bump{{Name: "react", From: "18.2.0", To: "18.3.0"}}
+	two := append(one, Bump{Name: "vite", From: "5.0.0", To: "5.1.0"})
+
+	if got := Subject(one); got != "bump react from 18.2.0 to 18.3.0" {
+		t.Errorf("Subject(one) = %q", got)
+	}
+	if got := Body(one); got != "" {
+		t.Errorf("Body(one) = %q", got)
+	}
+	if got := Subject(two); got != "bump 2 dependencies" {
+		t.Errorf("Subject(two) = %q", got)
+	}
+	if got, want := Body(two), "- bump react from 18.2.0 to 18.3.0\n- bump vite from 5.0.0 to 5.1.0"; got != want {
+		t.Errorf("Body(two) = %q, want %q", got, want)
+	}
+}
+
+func TestIsManifestAndLockFile(t *testing.T) {
+	for file, want := range map[string][2]bool{
+		"go.mod":                {true, false},
+		"web/package.json":      {true, false},
+		"go.sum":                {false, true},
+		"web/pnpm-lock.yaml":    {false, true},
+		"internal/deps/deps.go": {false, false},
+	} {
+		if got := [2]bool{IsManifest(file), IsLockFile(file)}; got != want {
+			t.Errorf("%s: IsManifest, IsLockFile = %v, want %v", file, got, want)
+		}
+	}
+}
+
+// replace replaces the first old in s, which must be there.
+func replace(s, old, new string) string {
+	if !strings.Contains(s, old) {
+		panic("replace: " + old + " not found")
+	}
+	return strings.Replace(s, old, new, 1)
+}
@@ -0,0 +1,257 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/model"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/fatih/color"
+)
+
+// minGitVersion is the oldest git with status --porcelain=v2, which staging
+// relies on.
+var minGitVersion = []int{2, 11}
+
+const doctorTimeout = 30 * time.Second
+
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+type check struct {
+	Name   string
+	Status checkStatus
+	Detail string
+	Fix    string
+}
+
+// Doctor checks everything geminic depends on and prints a report with a fix
+// for each problem. It fails when any check does.
+func Doctor(ctx context.Context) error {
+	var checks []check
+	checks = append(checks, checkGit()...)
+
+	cfg, configChecks := checkConfig()
+	checks = append(checks, configChecks...)
+	if cfg != nil {
+		checks = append(checks, checkProvider(ctx, cfg)...)
+	}
+
+	failed := 0
+	warned := 0
+	for _, c := range checks {
+		mark := color.New(color.FgGreen).Sprint("✓")
+		switch c.Status {
+		case checkWarn:
+			mark = color.New(color.FgYellow).Sprint("!")
+			warned++
+		case checkFail:
+			mark = color.New(color.FgRed).Sprint("✗")
+			failed++
+		}
+		fmt.Printf("%s %s: %s\n", mark, color.New(color.Bold).Sprint(c.Name), c.Detail)
+		if c.Fix != "" && c.Status != checkPass {
+			fmt.Printf("  %s\n", i18n.T("doctor.fix", c.Fix))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(i18n.T("doctor.summary", len(checks)-failed-warned, warned, failed))
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func checkGit() []check {
+	gitService := service.GetGitService()
+
+	version, err := gitService.GitVersion()
+	if err != nil {
+		return []check{{Name: "git", Status: checkFail, Detail: err.Error(), Fix: i18n.T("doctor.git_install")}}
+	}
+	checks := []check{{Name: "git", Status: checkPass, Detail: version}}
+	if compareVersion(version, minGitVersion) < 0 {
+		checks[0].Status = checkFail
+		checks[0].Fix = i18n.T("doctor.git_upgrade", joinVersion(minGitVersion))
+	}
+
+	root, err := gitService.RepoRoot()
+	if err != nil {
+		return append(checks, check{Name: i18n.T("doctor.repo"), Status: checkWarn, Detail: i18n.T("doctor.not_repo"), Fix: i18n.T("doctor.not_repo_fix")})
+	}
+
+	repo := check{Name: i18n.T("doctor.repo"), Status: checkPass, Detail: root}
+	operation, err := gitService.OperationInProgress()
+	switch {
+	case err != nil:
+		repo.Status = checkFail
+		repo.Detail = err.Error()
+	case operation != "":
+		repo.Status = checkWarn
+		repo.Detail = i18n.T("doctor.in_progress", root, operation)
+		repo.Fix = i18n.T("doctor.in_progress_fix", operation)
+	case gitService.RefExists("HEAD") && gitService.IsDetachedHead():
+		repo.Status = checkWarn
+		repo.Detail = i18n.T("doctor.detached", root)
+		repo.Fix = i18n.T("doctor.detached_fix")
+	}
+	return append(checks, repo)
+}
+
+// checkConfig returns the loaded config, or nil when it cannot be used for
+// the remaining checks.
+func checkConfig() (*model.Config, []check) {
+	name := i18n.T("doctor.config")
+	path, err := model.ConfigPath()
+	if err != nil {
+		return nil, []check{{Name: name, Status: checkFail, Detail: err.Error(), Fix: i18n.T("doctor.config_flag")}}
+	}
+
+	var checks []check
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		checks = append(checks, check{Name: name, Status: checkWarn, Detail: i18n.T("doctor.config_missing", path), Fix: i18n.T("doctor.config_create")})
+	case err != nil:
+		return nil, []check{{Name: name, Status: checkFail, Detail: err.Error(), Fix: i18n.T("doctor.config_flag")}}
+	default:
+		if _, err := os.ReadFile(path); err != nil {
+			return nil, []check{{Name: name, Status: checkFail, Detail: err.Error(), Fix: i18n.T("doctor.config_chmod", path)}}
+		}
+		// The file holds the key, nobody but its owner should be able to read it.
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+			checks = append(checks, check{Name: name, Status: checkWarn, Detail: i18n.T("doctor.config_mode", path, info.Mode().Perm()), Fix: i18n.T("doctor.config_chmod", path)})
+		}
+	}
+
+	var cfg model.Config
+	if err := cfg.Load(); err != nil {
+		return nil, append(checks, check{Name: name, Status: checkFail, Detail: err.Error(), Fix: i18n.T("doctor.config_fix")})
+	}
+	i18n.SetLanguage(cfg.Language)
+	if len(checks) == 0 {
+		checks = append(checks, check{Name: name, Status: checkPass, Detail: path})
+	}
+	return &cfg, checks
+}
+
+func checkProvider(ctx context.Context, cfg *model.Config) []check {
+	provider := cfg.ModelProvider
+	if provider == "" {
+		provider = model_provider.OpenAI
+	}
+	if provider == model_provider.Heuristic {
+		return []check{{Name: i18n.T("doctor.provider"), Status: checkPass, Detail: i18n.T("doctor.offline")}}
+	}
+
+	if cfg.Key == "" {
+		return []check{{Name: i18n.T("doctor.key"), Status: checkFail, Detail: i18n.T("doctor.key_missing"), Fix: i18n.T("doctor.key_fix")}}
+	}
+	checks := []check{{Name: i18n.T("doctor.key"), Status: checkPass, Detail: i18n.T("doctor.key_set")}}
+
+	// Probe the configured provider alone: no fallbacks, no cache and a
+	// single attempt, so that the report says what is wrong with it.
+	probeCfg := *cfg
+	probeCfg.Cache.Enabled = false
+	probeCfg.Retry.MaxAttempts = 1
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	endpoint := provider
+	if cfg.UseCustom() {
+		endpoint = fmt.Sprintf("%s (%s)", provider, cfg.CustomURL)
+	}
+	client, err := llm.GetLLM(ctx, &probeCfg)
+	if err != nil {
+		return append(checks, check{Name: i18n.T("doctor.endpoint"), Status: checkFail, Detail: err.Error(), Fix: i18n.T("doctor.config_fix")})
+	}
+	models, err := client.ModelList(ctx)
+	if err != nil {
+		return append(checks, check{Name: i18n.T("doctor.endpoint"), Status: checkFail, Detail: err.Error(), Fix: fixFor(err, i18n.T("doctor.endpoint_fix"))})
+	}
+	checks = append(checks, check{Name: i18n.T("doctor.endpoint"), Status: checkPass, Detail: i18n.T("doctor.endpoint_ok", endpoint, len(models))})
+
+	if cfg.Model == "" {
+		return append(checks, check{Name: i18n.T("doctor.model"), Status: checkFail, Detail: i18n.T("doctor.model_missing"), Fix: i18n.T("doctor.model_fix")})
+	}
+	if !hasModel(models, cfg.Model) {
+		return append(checks, check{Name: i18n.T("doctor.model"), Status: checkFail, Detail: i18n.T("doctor.model_unknown", cfg.Model), Fix: i18n.T("doctor.model_fix")})
+	}
+	checks = append(checks, check{Name: i18n.T("doctor.model"), Status: checkPass, Detail: cfg.Model})
+
+	structured := check{Name: i18n.T("doctor.structured"), Status: checkPass, Detail: i18n.T("doctor.structured_ok")}
+	resp, err := client.Generate(ctx, &llm.Request{
+		Kind:    llm.KindCommit,
+		Prompt:  "Write the commit message for a change that fixes a typo in README.md.",
+		NoCache: true,
+	})
+	switch {
+	case err != nil:
+		structured.Status = checkFail
+		structured.Detail = err.Error()
+		structured.Fix = fixFor(err, i18n.T("doctor.structured_fix"))
+	case resp.Commit == nil || resp.Commit.Msg == "":
+		structured.Status = checkFail
+		structured.Detail = i18n.T("doctor.structured_empty", cfg.Model)
+		structured.Fix = i18n.T("doctor.structured_fix")
+	}
+	return append(checks, structured)
+}
+
+// hasModel reports whether name is one of models, which Gemini lists with a
+// models/ prefix.
+func hasModel(models []string, name string) bool {
+	return slices.ContainsFunc(models, func(m string) bool {
+		return m == name || strings.TrimPrefix(m, "models/") == strings.TrimPrefix(name, "models/")
+	})
+}
+
+func fixFor(err error, fallback string) string {
+	if hint := llm.Hint(err); hint != "" {
+		return hint
+	}
+	return fallback
+}
+
+// compareVersion compares the leading numbers of a version such as
+// "2.39.3 (Apple Git-146)" with want.
+func compareVersion(version string, want []int) int {
+	fields := strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == ' ' })
+	for i, w := range want {
+		n := 0
+		if i < len(fields) {
+			n, _ = strconv.Atoi(fields[i])
+		}
+		if n != w {
+			if n < w {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func joinVersion(version []int) string {
+	parts := make([]string, len(version))
+	for i, n := range version {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
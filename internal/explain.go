@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+	"github.com/Beriholic/geminic/internal/usage"
+)
+
+func ExplainHistory(ctx context.Context, target string, maxCount int) error {
+	gitService := service.GetGitService()
+
+	if err := gitService.VerifyGitInstallation(); err != nil {
+		return err
+	}
+	if err := gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+
+	history, err := gitService.LoadHistory(target, maxCount)
+	if err != nil {
+		return err
+	}
+
+	llmService, err := service.NewLLMServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *llm.Response, 1)
+
+	err = ui.RenderStreamSpinner(i18n.T("explain.explaining", target), func(progress func(string, int, bool)) {
+		resp, err := llmService.Explain(ctx, history, func(chunk llm.Chunk) {
+			progress(chunk.Text, chunk.Tokens, chunk.Estimated)
+		})
+		errChan <- err
+		respChan <- resp
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := <-respChan, <-errChan
+	if err != nil {
+		return err
+	}
+	recordUsage(resp, usage.KindExplain, "")
+
+	fmt.Println(ui.FormatText(i18n.T("explain.explanation", target), resp.Text))
+	return nil
+}
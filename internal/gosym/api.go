@@ -0,0 +1,150 @@
+package gosym
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// API returns the name and the exported API of a package given the source of
+// its files. The API is keyed by declaration, e.g. "func New" or
+// "field Config.Key", with the declared type as value. Consts and vars are
+// recorded by their declared type only, so a changed value, or a changed type
+// that is inferred from the value, is not seen.
+func API(files map[string][]byte) (string, map[string]string, error) {
+	fset := token.NewFileSet()
+	api := make(map[string]string)
+	pkgName := ""
+
+	for filename, src := range files {
+		file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse %s. %v", filename, err)
+		}
+		pkgName = file.Name.Name
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if !decl.Name.IsExported() {
+					continue
+				}
+				if decl.Recv == nil || len(decl.Recv.List) == 0 {
+					api["func "+decl.Name.Name] = sourceString(fset, decl.Type)
+					continue
+				}
+
+				recv := decl.Recv.List[0].Type
+				recvName := receiverName(recv)
+				if !ast.IsExported(recvName) {
+					continue
+				}
+				recvType := recvName
+				if _, ok := recv.(*ast.StarExpr); ok {
+					recvType = "*" + recvName
+				}
+				api[fmt.Sprintf("method %s.%s", recvName, decl.Name.Name)] = fmt.Sprintf("(%s) %s", recvType, sourceString(fset, decl.Type))
+			case *ast.GenDecl:
+				addGenDecl(fset, api, decl)
+			}
+		}
+	}
+
+	return pkgName, api, nil
+}
+
+func addGenDecl(fset *token.FileSet, api map[string]string, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			for _, name := range spec.Names {
+				if name.IsExported() {
+					api[fmt.Sprintf("%s %s", decl.Tok, name.Name)] = sourceString(fset, spec.Type)
+				}
+			}
+		case *ast.TypeSpec:
+			if !spec.Name.IsExported() {
+				continue
+			}
+			name := spec.Name.Name
+
+			typeParams := ""
+			if spec.TypeParams != nil {
+				typeParams = sourceString(fset, spec.TypeParams)
+			}
+			if spec.Assign.IsValid() {
+				typeParams += "="
+			}
+
+			switch typ := spec.Type.(type) {
+			case *ast.StructType:
+				api["type "+name] = typeParams + "struct"
+				for _, field := range typ.Fields.List {
+					fieldType := sourceString(fset, field.Type)
+					if len(field.Names) == 0 {
+						if embedded := receiverName(field.Type); ast.IsExported(embedded) {
+							api[fmt.Sprintf("field %s.%s", name, embedded)] = fieldType
+						}
+					}
+					for _, fieldName := range field.Names {
+						if fieldName.IsExported() {
+							api[fmt.Sprintf("field %s.%s", name, fieldName.Name)] = fieldType
+						}
+					}
+				}
+			case *ast.InterfaceType:
+				api["type "+name] = typeParams + "interface"
+				for _, method := range typ.Methods.List {
+					methodType := sourceString(fset, method.Type)
+					if len(method.Names) == 0 {
+						api[fmt.Sprintf("embed %s.%s", name, methodType)] = ""
+					}
+					for _, methodName := range method.Names {
+						api[fmt.Sprintf("method %s.%s", name, methodName.Name)] = methodType
+					}
+				}
+			default:
+				api["type "+name] = typeParams + sourceString(fset, spec.Type)
+			}
+		}
+	}
+}
+
+// Breaking lists the changes from old to new that can break code using the
+// package: removed or changed declarations, and methods or embedded types
+// added to an interface, which existing implementations do not have.
+func Breaking(old, new map[string]string) []string {
+	var breaking []string
+	for key, before := range old {
+		after, ok := new[key]
+		switch {
+		case !ok:
+			breaking = append(breaking, "removed "+key)
+		case before == after, strings.Replace(before, "(*", "(", 1) == after:
+			// a method moved from a pointer to a value receiver is still in
+			// the method set of the pointer
+		default:
+			breaking = append(breaking, fmt.Sprintf("changed %s from %s to %s", key, before, after))
+		}
+	}
+
+	for key := range new {
+		if _, ok := old[key]; ok {
+			continue
+		}
+		kind, name, _ := strings.Cut(key, " ")
+		if kind != "method" && kind != "embed" {
+			continue
+		}
+		typeName, member, _ := strings.Cut(name, ".")
+		if strings.HasSuffix(old["type "+typeName], "interface") && strings.HasSuffix(new["type "+typeName], "interface") {
+			breaking = append(breaking, fmt.Sprintf("added %s %s to interface %s", kind, member, typeName))
+		}
+	}
+
+	sort.Strings(breaking)
+	return breaking
+}
@@ -0,0 +1,115 @@
+package gosym
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustAPI(t *testing.T, src string) map[string]string {
+	t.Helper()
+	_, api, err := API(map[string][]byte{"x.go": []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestAPI(t *testing.T) {
+	name, api, err := API(map[string][]byte{
+		"a.go": []byte("package x\nconst Max int = 3\nvar hidden = 1\nfunc New(n int) *T { return nil }"),
+		"b.go": []byte("package x\ntype T struct {\n\tKey string\n\tn int\n\tio.Reader\n}\nfunc (T) Len() int { return 0 }\ntype I interface{ Do() error }"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"const Max":    "int",
+		"func New":     "func(n int) *T",
+		"type T":       "struct",
+		"field T.Key":  "string",
+		"method T.Len": "(T) func() int",
+		"type I":       "interface",
+		"method I.Do":  "func() error",
+	}
+	if name != "x" || !reflect.DeepEqual(api, want) {
+		t.Errorf("API() = %q, %#v\nwant %#v", name, api, want)
+	}
+}
+
+func TestBreaking(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []string
+	}{
+		{
+			name: "added declarations",
+			old:  "package x\nfunc A() {}",
+			new:  "package x\nfunc A() {}\nfunc B() {}\ntype S struct{ N int }",
+		},
+		{
+			name: "removed func",
+			old:  "package x\nfunc A() {}\nfunc B() {}",
+			new:  "package x\nfunc A() {}",
+			want: []string{"removed func B"},
+		},
+		{
+			name: "changed signature and field type",
+			old:  "package x\nfunc A(n int) {}\ntype S struct{ N int }",
+			new:  "package x\nfunc A(n int64) {}\ntype S struct{ N string }",
+			want: []string{
+				"changed field S.N from int to string",
+				"changed func A from func(n int) to func(n int64)",
+			},
+		},
+		{
+			name: "pointer to value receiver",
+			old:  "package x\ntype T int\nfunc (*T) M() {}",
+			new:  "package x\ntype T int\nfunc (T) M() {}",
+		},
+		{
+			name: "value to pointer receiver",
+			old:  "package x\ntype T int\nfunc (T) M() {}",
+			new:  "package x\ntype T int\nfunc (*T) M() {}",
+			want: []string{"changed method T.M from (T) func() to (*T) func()"},
+		},
+		{
+			name: "method added to interface",
+			old:  "package x\ntype I interface{ A() }",
+			new:  "package x\ntype I interface {\n\tA()\n\tB()\n}",
+			want: []string{"added method B to interface I"},
+		},
+		{
+			name: "method added to new interface",
+			old:  "package x",
+			new:  "package x\ntype I interface{ A() }",
+		},
+		{
+			name: "removed exported const and var",
+			old:  "package x\nconst Max int = 3\nvar Default, other = 1, 2",
+			new:  "package x\nvar other = 2",
+			want: []string{"removed const Max", "removed var Default"},
+		},
+		{
+			name: "added exported const and var",
+			old:  "package x",
+			new:  "package x\nconst Max = 3\nvar Default = 1",
+		},
+		{
+			name: "const value is not compared",
+			old:  "package x\nconst N int = 1",
+			new:  "package x\nconst N int = 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Breaking(mustAPI(t, tt.old), mustAPI(t, tt.new))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Breaking() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
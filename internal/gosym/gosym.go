@@ -0,0 +1,162 @@
+package gosym
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+)
+
+const (
+	KindFunc   = "func"
+	KindMethod = "method"
+	KindType   = "type"
+	KindConst  = "const"
+	KindVar    = "var"
+)
+
+// Symbol is a top level declaration of a Go file. Source is the declaration
+// printed without comments on a single line, so two versions of a symbol
+// compare equal unless the code changed.
+type Symbol struct {
+	Kind     string
+	Name     string
+	Exported bool
+	Source   string
+}
+
+// Parse returns the funcs, methods, types, consts and vars declared in src
+// keyed by name, methods as "(Recv).Name".
+func Parse(filename string, src []byte) (map[string]Symbol, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s. %v", filename, err)
+	}
+
+	symbols := make(map[string]Symbol)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			fn := *decl
+			fn.Doc = nil
+
+			symbol := Symbol{Kind: KindFunc, Name: fn.Name.Name, Exported: fn.Name.IsExported()}
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				recv := nodeString(fset, fn.Recv.List[0].Type)
+				symbol.Kind = KindMethod
+				symbol.Name = fmt.Sprintf("(%s).%s", recv, fn.Name.Name)
+				symbol.Exported = symbol.Exported && ast.IsExported(receiverName(fn.Recv.List[0].Type))
+			}
+			symbol.Source = sourceString(fset, &fn)
+			symbols[symbol.Name] = symbol
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					typeSpec := *spec
+					typeSpec.Doc, typeSpec.Comment = nil, nil
+					symbols[typeSpec.Name.Name] = Symbol{
+						Kind:     KindType,
+						Name:     typeSpec.Name.Name,
+						Exported: typeSpec.Name.IsExported(),
+						Source:   sourceString(fset, &typeSpec),
+					}
+				case *ast.ValueSpec:
+					// names declared together, e.g. var a, b = 1, 2, share
+					// their source
+					valueSpec := *spec
+					valueSpec.Doc, valueSpec.Comment = nil, nil
+					kind := KindVar
+					if decl.Tok == token.CONST {
+						kind = KindConst
+					}
+					for _, name := range valueSpec.Names {
+						if name.Name == "_" {
+							continue
+						}
+						symbols[name.Name] = Symbol{
+							Kind:     kind,
+							Name:     name.Name,
+							Exported: name.IsExported(),
+							Source:   kind + " " + sourceString(fset, &valueSpec),
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return symbols, nil
+}
+
+// Diff compares the symbols of two versions of file. A nil map stands for a
+// file that does not exist on that side.
+func Diff(file string, old, new map[string]Symbol) []dto.SymbolChange {
+	var changes []dto.SymbolChange
+	for name, symbol := range new {
+		before, ok := old[name]
+		switch {
+		case !ok:
+			changes = append(changes, change(file, symbol, dto.SymbolAdded))
+		case before.Source != symbol.Source:
+			changes = append(changes, change(file, symbol, dto.SymbolModified))
+		}
+	}
+	for name, symbol := range old {
+		if _, ok := new[name]; !ok {
+			changes = append(changes, change(file, symbol, dto.SymbolRemoved))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Change != changes[j].Change {
+			return changes[i].Change < changes[j].Change
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func change(file string, symbol Symbol, kind string) dto.SymbolChange {
+	return dto.SymbolChange{
+		File:     file,
+		Kind:     symbol.Kind,
+		Name:     symbol.Name,
+		Change:   kind,
+		Exported: symbol.Exported,
+	}
+}
+
+// receiverName returns the type name of a receiver such as *T or T[K].
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+func nodeString(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+func sourceString(fset *token.FileSet, node any) string {
+	return strings.Join(strings.Fields(nodeString(fset, node)), " ")
+}
@@ -0,0 +1,118 @@
+package gosym
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+)
+
+func mustParse(t *testing.T, src string) map[string]Symbol {
+	t.Helper()
+	if src == "" {
+		return nil
+	}
+	symbols, err := Parse("x.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return symbols
+}
+
+func TestParse(t *testing.T) {
+	symbols := mustParse(t, `package x
+
+// New makes a T.
+func New() *T { return &T{} }
+
+type T struct{ n int }
+
+func (t *T) Len() int { return t.n }
+
+func (t *t2) Len() int { return 0 }
+
+type t2 struct{}
+`)
+
+	want := map[string]Symbol{
+		"New":       {Kind: KindFunc, Name: "New", Exported: true, Source: "func New() *T { return &T{} }"},
+		"T":         {Kind: KindType, Name: "T", Exported: true, Source: "T struct{ n int }"},
+		"(*T).Len":  {Kind: KindMethod, Name: "(*T).Len", Exported: true, Source: "func (t *T) Len() int { return t.n }"},
+		"(*t2).Len": {Kind: KindMethod, Name: "(*t2).Len", Exported: false, Source: "func (t *t2) Len() int { return 0 }"},
+		"t2":        {Kind: KindType, Name: "t2", Exported: false, Source: "t2 struct{}"},
+	}
+	if !reflect.DeepEqual(symbols, want) {
+		t.Errorf("Parse() = %#v\nwant %#v", symbols, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []dto.SymbolChange
+	}{
+		{
+			name: "new file",
+			new:  "package x\nfunc A() {}",
+			want: []dto.SymbolChange{{File: "x.go", Kind: KindFunc, Name: "A", Change: dto.SymbolAdded, Exported: true}},
+		},
+		{
+			name: "deleted file",
+			old:  "package x\nfunc a() {}",
+			want: []dto.SymbolChange{{File: "x.go", Kind: KindFunc, Name: "a", Change: dto.SymbolRemoved}},
+		},
+		{
+			name: "comments and formatting only",
+			old:  "package x\n// A does a.\nfunc A() {\n\treturn\n}",
+			new:  "package x\n// A does a thing.\nfunc A() { return }",
+		},
+		{
+			name: "added exported const and var",
+			old:  "package x",
+			new:  "package x\n// Max is the limit.\nconst Max = 3\nvar (\n\tDefault = New()\n\tcache map[string]int\n)",
+			want: []dto.SymbolChange{
+				{File: "x.go", Kind: KindVar, Name: "Default", Change: dto.SymbolAdded, Exported: true},
+				{File: "x.go", Kind: KindConst, Name: "Max", Change: dto.SymbolAdded, Exported: true},
+				{File: "x.go", Kind: KindVar, Name: "cache", Change: dto.SymbolAdded},
+			},
+		},
+		{
+			name: "removed exported const and var",
+			old:  "package x\nconst (\n\tA = iota\n\tB\n)\nvar ErrX, errY = errors.New(\"x\"), errors.New(\"y\")\nvar _ = A",
+			new:  "package x\nconst A = iota\nvar errY = errors.New(\"y\")",
+			want: []dto.SymbolChange{
+				{File: "x.go", Kind: KindVar, Name: "errY", Change: dto.SymbolModified},
+				{File: "x.go", Kind: KindConst, Name: "B", Change: dto.SymbolRemoved, Exported: true},
+				{File: "x.go", Kind: KindVar, Name: "ErrX", Change: dto.SymbolRemoved, Exported: true},
+			},
+		},
+		{
+			name: "changed const value",
+			old:  "package x\nconst Max = 3",
+			new:  "package x\nconst Max = 4",
+			want: []dto.SymbolChange{{File: "x.go", Kind: KindConst, Name: "Max", Change: dto.SymbolModified, Exported: true}},
+		},
+		{
+			name: "sorted by change then name",
+			old:  "package x\nfunc B() {}\nfunc C() {}\ntype D int",
+			new:  "package x\nfunc A() {}\nfunc B() { println() }\ntype D string",
+			want: []dto.SymbolChange{
+				{File: "x.go", Kind: KindFunc, Name: "A", Change: dto.SymbolAdded, Exported: true},
+				{File: "x.go", Kind: KindFunc, Name: "B", Change: dto.SymbolModified, Exported: true},
+				{File: "x.go", Kind: KindType, Name: "D", Change: dto.SymbolModified, Exported: true},
+				{File: "x.go", Kind: KindFunc, Name: "C", Change: dto.SymbolRemoved, Exported: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff("x.go", mustParse(t, tt.old), mustParse(t, tt.new))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,13 +8,27 @@ import (
 	"strings"
 
 	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/llm"
 	"github.com/Beriholic/geminic/internal/model/dto"
 	"github.com/Beriholic/geminic/internal/service"
 	"github.com/Beriholic/geminic/internal/ui"
+	"github.com/Beriholic/geminic/internal/usage"
 	"github.com/fatih/color"
 )
 
-func GeneratorCommit(ctx context.Context, userCommit string) error {
+type CommitOptions struct {
+	UserCommit string
+	Review     bool
+	// All stages modified and deleted tracked files first, like git commit -a.
+	All bool
+	// Interactive picks the files to stage before generating.
+	Interactive bool
+	// CommitArgs are passed on to git commit after the configured defaults.
+	CommitArgs []string
+}
+
+func GeneratorCommit(ctx context.Context, opts CommitOptions) error {
 	gitService := service.GetGitService()
 
 	if err := gitService.VerifyGitInstallation(); err != nil {
@@ -24,17 +38,31 @@ func GeneratorCommit(ctx context.Context, userCommit string) error {
 		return err
 	}
 
+	commitArgs, err := resolveCommitArgs(opts.CommitArgs)
+	if err != nil {
+		return err
+	}
+	if err := stageChanges(opts, hasCommitArg(commitArgs, "--allow-empty")); err != nil {
+		return err
+	}
+	// git writes the message of a fixup commit itself and refuses -F with it.
+	if hasCommitArg(commitArgs, "--fixup") {
+		return gitService.CommitWithoutMessage(commitArgs...)
+	}
+
 	files, diff, err := gitService.DetectDiffChanges()
 	if err != nil {
 		return err
 	}
 
-	if len(files) == 0 {
+	if len(files) == 0 && hasCommitArg(commitArgs, "--allow-empty") {
+		fmt.Println(i18n.T("commit.empty"))
+	} else if len(files) == 0 {
 		return fmt.Errorf(
 			"no staged changes found. stage your changes manually",
 		)
 	} else {
-		fmt.Printf("Detected %v staged file:\n", len(files))
+		fmt.Println(i18n.T("commit.detected", len(files)))
 	}
 
 	relatedFiles := getRelatedFiles(files)
@@ -43,66 +71,418 @@ func GeneratorCommit(ctx context.Context, userCommit string) error {
 		relatedFilesArray = append(relatedFilesArray, fmt.Sprintf("%s/%s", dir, ls))
 	}
 
+	changes, err := gitService.StagedFileChanges()
+	if err != nil {
+		return err
+	}
+
+	base := stagedBase()
+	if bumps, onlyBumps := dependencyBumps(base, "", changes); onlyBumps {
+		return commitDependencyBumps(bumps, commitArgs)
+	}
+
 	llmService, err := service.NewLLMServer(ctx)
 	if err != nil {
 		return err
 	}
 
 	commitDTO := &dto.CommitDTO{
-		Commit: userCommit,
-		Diff:   diff,
-		Files:  files,
+		Commit:  opts.UserCommit,
+		Diff:    diff,
+		Files:   files,
+		Changes: changes,
 	}
+	describeChanges(commitDTO, base, "")
 
-	for {
-		errChan := make(chan error, 1)
-		genCommitChan := make(chan *dto.GitCommit, 1)
-
-		err := ui.RenderSpinner("Generating commit message...", func() {
-			genCommit, err := llmService.Generate(ctx, commitDTO)
-			errChan <- err
-			genCommitChan <- genCommit
-		})
+	if opts.Review && len(files) > 0 {
+		result, err := reviewStagedChanges(ctx, llmService, commitDTO)
 		if err != nil {
 			return err
 		}
+		fmt.Println(ui.FormatReview(result))
 
-		gitCommit, err := <-genCommitChan, <-errChan
-		if err != nil {
+		if high := result.Count(dto.SeverityHigh); high > 0 {
+			return fmt.Errorf("review found %d high severity finding(s), fix them or rerun with --review=false", high)
+		}
+	}
+
+	message, session, err := generateCommitMessage(ctx, llmService, commitDTO)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		fmt.Println(i18n.T("commit.cancelled"))
+		return nil
+	}
+
+	return commitMessage(message, commitArgs, session)
+}
+
+// commitMessage commits the staged changes with message, saving it for
+// geminic retry when git rejects it, and records session once the outcome is
+// known when there is one.
+func commitMessage(message string, commitArgs []string, session *session) error {
+	gitService := service.GetGitService()
+
+	err := gitService.CommitChanges(message, commitArgs...)
+	if session != nil {
+		session.committed(err)
+	}
+	if err != nil {
+		return saveFailedMessage(message, commitArgs, err)
+	}
+	fmt.Println(i18n.T("commit.committed"))
+	return gitService.RemoveSavedMessage()
+}
+
+// saveFailedMessage keeps the message of a commit git rejected, typically in
+// a hook, and the options it was run with, so geminic retry can reuse them
+// without asking the model again. A later successful commit removes them.
+func saveFailedMessage(message string, commitArgs []string, commitErr error) error {
+	path, err := service.GetGitService().SaveMessage(message, commitArgs)
+	if err != nil {
+		return fmt.Errorf("%v\n%v", commitErr, err)
+	}
+	return fmt.Errorf("%v\nthe message was saved to %s, fix the problem and run geminic retry", commitErr, path)
+}
+
+// stageChanges stages what opts asks for and lets the user pick files when
+// nothing is staged yet, then warns about files that are only partly staged.
+func stageChanges(opts CommitOptions, allowEmpty bool) error {
+	gitService := service.GetGitService()
+
+	if opts.All {
+		if err := gitService.StageTracked(); err != nil {
 			return err
 		}
+	}
 
-		genCommit := gitCommit.String()
+	files, err := gitService.Status()
+	if err != nil {
+		return err
+	}
 
-		fmt.Println(ui.FormatText("Generated commit message", genCommit))
+	staged := false
+	var unstaged []service.FileStatus
+	for _, file := range files {
+		if !file.Untracked && file.Staged != '.' {
+			staged = true
+		}
+		if file.HasUnstaged() {
+			unstaged = append(unstaged, file)
+		}
+	}
 
-		action, err := ui.RenderActionForm()
+	if len(unstaged) > 0 && (opts.Interactive || (!staged && !allowEmpty)) {
+		labels := make([]string, len(unstaged))
+		for idx, file := range unstaged {
+			labels[idx] = fmt.Sprintf("%-9s %s", statusLabel(file), file.Path)
+		}
+		picked, err := ui.RenderMultiSelect(i18n.T("form.select_files"), labels)
 		if err != nil {
 			return err
 		}
 
+		if len(picked) > 0 {
+			paths := make([]string, len(picked))
+			for idx, pick := range picked {
+				paths[idx] = unstaged[pick].Path
+			}
+			if err := gitService.StageFiles(paths...); err != nil {
+				return err
+			}
+			if files, err = gitService.Status(); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, file := range files {
+		if file.PartiallyStaged() {
+			color.New(color.FgYellow).Println(i18n.T("commit.partially_staged", file.Path))
+		}
+	}
+
+	return nil
+}
+
+func statusLabel(file service.FileStatus) string {
+	switch {
+	case file.Unmerged:
+		return i18n.T("status.conflict")
+	case file.Untracked:
+		return i18n.T("status.untracked")
+	}
+
+	switch file.Unstaged {
+	case 'D':
+		return i18n.T("status.deleted")
+	case 'T':
+		return i18n.T("status.typechange")
+	}
+	return i18n.T("status.modified")
+}
+
+// stagedBase is the revision the staged changes are compared with, HEAD, or
+// "" before the first commit.
+func stagedBase() string {
+	if service.GetGitService().RefExists("HEAD") {
+		return "HEAD"
+	}
+	return ""
+}
+
+// describeChanges adds what the model and the breaking change check need to
+// know about commitDTO.Changes besides the diff: the changed Go symbols, the
+// breaking API changes and the dependency bumps, and warns about removed and
+// broken API. The changes go from base, "" when there is nothing before them,
+// to rev, "" for the index.
+func describeChanges(commitDTO *dto.CommitDTO, base, rev string) {
+	commitDTO.Symbols = symbolChanges(base, rev, commitDTO.Changes)
+	commitDTO.Breaking = breakingChanges(base, rev, commitDTO.Changes)
+	bumps, _ := dependencyBumps(base, rev, commitDTO.Changes)
+	commitDTO.Bumps = make([]string, len(bumps))
+	for idx, bump := range bumps {
+		commitDTO.Bumps[idx] = bump.String()
+	}
+
+	for _, symbol := range commitDTO.Symbols {
+		if symbol.RemovesAPI() {
+			color.New(color.FgYellow).Println(i18n.T("commit.removes_api", symbol.File, symbol.Kind, symbol.Name))
+		}
+	}
+	if len(commitDTO.Breaking) > 0 {
+		color.New(color.FgYellow).Println(i18n.T("commit.breaking"))
+		for _, change := range commitDTO.Breaking {
+			fmt.Printf("\t%s\n", change)
+		}
+	}
+}
+
+// missingBreakingFooter reports, and tells the user, when the staged changes
+// break an exported API but message does not say so in a BREAKING CHANGE
+// footer.
+func missingBreakingFooter(commitDTO *dto.CommitDTO, message string) bool {
+	if len(commitDTO.Breaking) == 0 || dto.HasBreakingChangeFooter(message) {
+		return false
+	}
+
+	color.New(color.FgRed).Println(i18n.T("commit.breaking_required", dto.BreakingChangeFooter))
+	for _, change := range commitDTO.Breaking {
+		fmt.Printf("\t%s\n", change)
+	}
+	return true
+}
+
+// resolveCommitArgs prepends the commit_args configured for the current
+// repository to args.
+func resolveCommitArgs(args []string) ([]string, error) {
+	root, err := service.GetGitService().RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	commitArgs := append(config.Get().CommitArgsFor(root), args...)
+	if err := checkCommitArgs(commitArgs); err != nil {
+		return nil, err
+	}
+	return commitArgs, nil
+}
+
+// checkCommitArgs rejects options that give git commit a message of its own,
+// which would replace the generated one.
+func checkCommitArgs(args []string) error {
+	for _, arg := range args {
+		if arg == "--" {
+			return nil
+		}
+		for _, name := range []string{"--message", "--file"} {
+			if arg == name || strings.HasPrefix(arg, name+"=") {
+				return fmt.Errorf("%s is not supported, geminic writes the message", name)
+			}
+		}
+		for _, name := range []string{"-m", "-F"} {
+			if strings.HasPrefix(arg, name) {
+				return fmt.Errorf("%s is not supported, geminic writes the message", name)
+			}
+		}
+	}
+	return nil
+}
+
+// hasCommitArg reports whether the long option name appears in args, either
+// alone or as name=value.
+func hasCommitArg(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == name || strings.HasPrefix(arg, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// generateCommitMessage runs the generate/confirm loop and returns the message
+// the user accepted, or "" when they cancelled. When there is a message the
+// caller records the session with session.committed once it knows whether
+// git committed it.
+func generateCommitMessage(
+	ctx context.Context,
+	llmService *service.LLMService,
+	commitDTO *dto.CommitDTO,
+) (message string, session *session, err error) {
+	session = newSession(commitDTO)
+	defer func() {
+		if message == "" {
+			session.flush()
+		}
+	}()
+
+	generate := true
+	var history []llm.Message
+	for {
+		if generate {
+			errChan := make(chan error, 1)
+			respChan := make(chan *llm.Response, 1)
+
+			opts := service.GenerateOptions{
+				History: history,
+				// Rolling asks for a new message, so only the first candidate
+				// may be answered from the cache.
+				NoCache: len(session.candidates) > 0,
+			}
+
+			err := ui.RenderStreamSpinner(i18n.T("commit.generating"), func(progress func(string, int, bool)) {
+				opts.OnChunk = func(chunk llm.Chunk) {
+					progress(chunk.Text, chunk.Tokens, chunk.Estimated)
+				}
+				resp, err := llmService.Generate(ctx, commitDTO, opts)
+				errChan <- err
+				respChan <- resp
+			})
+			if err != nil {
+				return "", nil, err
+			}
+
+			resp, err := <-respChan, <-errChan
+			if err != nil {
+				return "", nil, err
+			}
+
+			if resp.FallbackReason != nil {
+				color.New(color.FgYellow).Println(i18n.T("commit.offline", resp.FallbackReason))
+			}
+			session.add(resp, history)
+			generate = false
+		}
+
+		current := session.candidates[session.current]
+
+		source := fmt.Sprintf("%s/%s", current.resp.Provider, current.resp.Model)
+		if current.resp.Cached {
+			source += ", " + i18n.T("commit.cached")
+		}
+		if len(session.candidates) > 1 {
+			source += fmt.Sprintf(", %d/%d", session.current+1, len(session.candidates))
+		}
+		fmt.Println(ui.FormatText(
+			i18n.T("commit.generated", source),
+			current.message,
+		))
+
+		action, err := ui.RenderActionForm(
+			session.current > 0,
+			session.current < len(session.candidates)-1,
+		)
+		if err != nil {
+			return "", nil, err
+		}
+
 		switch action {
 		case ui.CONFIRM:
-			fmt.Println("committed")
-			return gitService.CommitChanges(genCommit)
+			if missingBreakingFooter(commitDTO, current.message) {
+				continue
+			}
+			session.finish(usage.OutcomeConfirmed, "")
+			return current.message, session, nil
 		case ui.REGENERATE:
-			continue
-		case ui.EDIT_COMMIT:
-			_, err := ui.RenderEditorForm(genCommit)
+			history = current.history
+			generate = true
+		case ui.REFINE:
+			feedback, err := ui.RenderFeedbackForm()
 			if err != nil {
-				return err
+				return "", nil, err
 			}
-			return nil
+			if strings.TrimSpace(feedback) == "" {
+				continue
+			}
+			history = session.refine(feedback)
+			generate = true
+		case ui.PREVIOUS:
+			session.current--
+		case ui.NEXT:
+			session.current++
+		case ui.EDIT_COMMIT:
+			editedCommit := current.message
+			for {
+				edited, action, err := ui.RenderEditorForm(editedCommit)
+				if err != nil {
+					return "", nil, err
+				}
+				if action != ui.CONFIRM {
+					session.finish(usage.OutcomeCancelled, "")
+					return "", nil, nil
+				}
+				editedCommit = edited
+				if !missingBreakingFooter(commitDTO, editedCommit) {
+					break
+				}
+			}
+			session.finish(usage.OutcomeEdited, editedCommit)
+			return editedCommit, session, nil
+		case ui.EDIT_EDITOR:
+			editedCommit := current.message
+			for {
+				editedCommit, err = editInExternalEditor(editedCommit)
+				if err != nil {
+					return "", nil, err
+				}
+				if editedCommit == "" {
+					fmt.Println(i18n.T("commit.aborted"))
+					session.finish(usage.OutcomeCancelled, "")
+					return "", nil, nil
+				}
+				if !missingBreakingFooter(commitDTO, editedCommit) {
+					break
+				}
+			}
+			session.finish(usage.OutcomeEdited, editedCommit)
+			return editedCommit, session, nil
 		case ui.CANCEL:
-			fmt.Println("cancelled")
-			return nil
+			session.finish(usage.OutcomeCancelled, "")
+			return "", nil, nil
 		default:
-			fmt.Println("invalid action")
-			return nil
+			fmt.Println(i18n.T("commit.invalid_action"))
+			return "", nil, nil
 		}
 	}
 }
 
+// editInExternalEditor lets the user finish message in the editor git would
+// use, with the staged changes listed as comments below it.
+func editInExternalEditor(message string) (string, error) {
+	gitService := service.GetGitService()
+
+	editor, err := gitService.Editor()
+	if err != nil {
+		return "", err
+	}
+	summary, _ := gitService.StagedSummary()
+
+	return ui.RenderExternalEditor(editor, message, gitService.CommentChar(message), summary)
+}
+
 func getRelatedFiles(files []string) map[string]string {
 	relatedFiles := make(map[string]string)
 	visitedDirs := make(map[string]bool)
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/model"
+)
+
+func TestHasCommitArg(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		arg  string
+		want bool
+	}{
+		{"alone", []string{"-S", "--allow-empty"}, "--allow-empty", true},
+		{"with value", []string{"--fixup=HEAD~2"}, "--fixup", true},
+		{"prefix of another option", []string{"--fixup-x"}, "--fixup", false},
+		{"after --", []string{"--", "--fixup"}, "--fixup", false},
+		{"missing", []string{"--no-verify"}, "--fixup", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasCommitArg(tt.args, tt.arg); got != tt.want {
+				t.Errorf("hasCommitArg(%q, %q) = %v, want %v", tt.args, tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCommitArgs(t *testing.T) {
+	initRepo(t)
+	root := runGit(t, "rev-parse", "--show-toplevel")
+	cfg := config.Get()
+	defer func() {
+		cfg.CommitArgs = nil
+		cfg.Repos = nil
+	}()
+
+	tests := []struct {
+		name    string
+		global  []string
+		repo    []string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"defaults", []string{"--signoff"}, []string{"-S"}, nil, []string{"--signoff", "-S"}, false},
+		// git takes the last of --verify and --no-verify
+		{"args after defaults", []string{"--verify"}, []string{"-S"}, []string{"--no-verify"}, []string{"--verify", "-S", "--no-verify"}, false},
+		{"args after --", nil, nil, []string{"--", "-m"}, []string{"--", "-m"}, false},
+		{"-m", nil, nil, []string{"-m", "fix"}, nil, true},
+		{"-F", nil, nil, []string{"-Fmsg.txt"}, nil, true},
+		{"--message", nil, nil, []string{"--message=fix"}, nil, true},
+		{"--file in the config", nil, []string{"--file", "msg.txt"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.CommitArgs = tt.global
+			cfg.Repos = []model.RepoConfig{{Path: root, CommitArgs: tt.repo}}
+
+			got, err := resolveCommitArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveCommitArgs(%q) error = %v, want error %v", tt.args, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveCommitArgs(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
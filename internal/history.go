@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/history"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+)
+
+type HistoryOptions struct {
+	Query string
+	// AllRepos lists entries of every repository instead of the current one.
+	AllRepos bool
+	// Staged only lists candidates generated for the currently staged diff.
+	Staged bool
+	Limit  int
+	// Print lists the entries instead of offering to reuse one.
+	Print bool
+	// CommitArgs are passed on to git commit after the configured defaults.
+	CommitArgs []string
+}
+
+func BrowseHistory(opts HistoryOptions) error {
+	gitService := service.GetGitService()
+
+	filter := history.Filter{Query: opts.Query}
+	if !opts.AllRepos || opts.Staged {
+		repo, err := gitService.RepoRoot()
+		if err != nil {
+			return fmt.Errorf("%v. use --all to browse every repository", err)
+		}
+		filter.Repo = repo
+	}
+	if opts.Staged {
+		files, diff, err := gitService.DetectDiffChanges()
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no staged changes found")
+		}
+		filter.DiffHash = history.DiffHash(diff)
+	}
+
+	entries, err := history.Load()
+	if err != nil {
+		return err
+	}
+	entries = history.Search(entries, filter)
+	if opts.Limit > 0 && len(entries) > opts.Limit {
+		entries = entries[:opts.Limit]
+	}
+	if len(entries) == 0 {
+		fmt.Println(i18n.T("history.empty"))
+		return nil
+	}
+
+	if opts.Print {
+		printHistory(entries)
+		return nil
+	}
+
+	labels := make([]string, len(entries))
+	for idx, entry := range entries {
+		labels[idx] = historyLabel(entry)
+	}
+	selected, err := ui.RenderIndexSelect(i18n.T("form.pick_history"), labels)
+	if err != nil || selected < 0 {
+		return err
+	}
+
+	if err := gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+	commitArgs, err := resolveCommitArgs(opts.CommitArgs)
+	if err != nil {
+		return err
+	}
+	changes, err := gitService.StagedFileChanges()
+	if err != nil {
+		return err
+	}
+	commitDTO := &dto.CommitDTO{Breaking: breakingChanges(stagedBase(), "", changes)}
+
+	message := entries[selected].FinalMessage()
+	fmt.Println(ui.FormatText(i18n.T("history.message"), message))
+	for {
+		message, err = confirmMessage(message)
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			fmt.Println(i18n.T("commit.cancelled"))
+			return nil
+		}
+		if !missingBreakingFooter(commitDTO, message) {
+			break
+		}
+	}
+
+	return commitMessage(message, commitArgs, nil)
+}
+
+// confirmMessage asks whether to commit with a message that was not just
+// generated, letting the user edit it first. It returns "" when cancelled.
+func confirmMessage(message string) (string, error) {
+	action, err := ui.RenderReuseForm()
+	if err != nil {
+		return "", err
+	}
+
+	switch action {
+	case ui.CONFIRM:
+		return message, nil
+	case ui.EDIT_COMMIT:
+		editedCommit, action, err := ui.RenderEditorForm(message)
+		if err != nil {
+			return "", err
+		}
+		if action != ui.CONFIRM {
+			return "", nil
+		}
+		return editedCommit, nil
+	case ui.EDIT_EDITOR:
+		editedCommit, err := editInExternalEditor(message)
+		if err != nil {
+			return "", err
+		}
+		if editedCommit == "" {
+			fmt.Println(i18n.T("commit.aborted"))
+		}
+		return editedCommit, nil
+	}
+	return "", nil
+}
+
+func historyLabel(entry history.Entry) string {
+	subject, _, _ := strings.Cut(entry.FinalMessage(), "\n")
+	return fmt.Sprintf("%s  %-9s  %s", entry.Time.Local().Format("2006-01-02 15:04"), entry.Outcome, subject)
+}
+
+func printHistory(entries []history.Entry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, i18n.T("history.header"))
+	for _, entry := range entries {
+		subject, _, _ := strings.Cut(entry.FinalMessage(), "\n")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Local().Format(time.DateTime),
+			entry.Outcome,
+			entry.Model,
+			entry.DiffHash,
+			subject,
+		)
+	}
+	w.Flush()
+}
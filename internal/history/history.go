@@ -0,0 +1,141 @@
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/xdg"
+)
+
+// Entry is one generated commit message candidate.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Repo     string    `json:"repo"`
+	DiffHash string    `json:"diff_hash"`
+	Provider string    `json:"provider"`
+	Model    string    `json:"model"`
+	Message  string    `json:"message"`
+	// Edited is the message that was committed when the user edited the
+	// candidate before confirming it.
+	Edited  string `json:"edited,omitempty"`
+	Outcome string `json:"outcome"`
+}
+
+// FinalMessage is the message that was, or would have been, committed.
+func (e Entry) FinalMessage() string {
+	if e.Edited != "" {
+		return e.Edited
+	}
+	return e.Message
+}
+
+func DiffHash(diff string) string {
+	hash := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(hash[:8])
+}
+
+func storePath() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+func Append(entries ...Entry) error {
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %v", err)
+	}
+	defer file.Close()
+
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write history: %v", err)
+		}
+	}
+	return nil
+}
+
+// Load returns every entry, newest first.
+func Load() ([]Entry, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %v", err)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+type Filter struct {
+	// Query matches the message case-insensitively.
+	Query    string
+	Repo     string
+	DiffHash string
+}
+
+func Search(entries []Entry, filter Filter) []Entry {
+	query := strings.ToLower(filter.Query)
+
+	var matched []Entry
+	for _, entry := range entries {
+		if filter.Repo != "" && entry.Repo != filter.Repo {
+			continue
+		}
+		if filter.DiffHash != "" && entry.DiffHash != filter.DiffHash {
+			continue
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(entry.Message), query) &&
+			!strings.Contains(strings.ToLower(entry.Edited), query) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	return matched
+}
@@ -0,0 +1,272 @@
+package i18n
+
+var catalog = map[string]map[string]string{
+	"en_US": {
+		"action.yes":         "Yes",
+		"action.roll":        "Roll",
+		"action.refine":      "Refine",
+		"action.previous":    "Previous",
+		"action.next":        "Next",
+		"action.edit":        "Edit",
+		"action.open_editor": "Open in editor",
+		"action.no":          "No",
+
+		"form.confirm":              "Is that what you want?",
+		"form.feedback":             "How should it change?",
+		"form.feedback_placeholder": "shorter, mention the migration, scope should be api...",
+		"form.reuse":                "Commit the staged changes with this message?",
+		"form.edit":                 "Edit commit message",
+		"form.edit_confirm":         "Confirm edit?",
+		"form.select_model":         "Selecting a Gemini Model.",
+		"form.select_files":         "Select the files to stage",
+		"form.pick_history":         "Pick a message to reuse",
+
+		"editor.help":    "Please enter the commit message for your changes. Lines starting\nwith '%s' will be ignored, and an empty message aborts the commit.",
+		"editor.changes": "Changes to be committed:",
+
+		"status.conflict":   "conflict",
+		"status.untracked":  "untracked",
+		"status.deleted":    "deleted",
+		"status.typechange": "typechange",
+		"status.modified":   "modified",
+
+		"commit.detected":          "Detected %v staged file:",
+		"commit.empty":             "No staged changes, creating an empty commit",
+		"commit.generating":        "Generating commit message...",
+		"commit.generated":         "Generated commit message (%s)",
+		"commit.cached":            "cached",
+		"commit.cancelled":         "cancelled",
+		"commit.committed":         "committed",
+		"commit.aborted":           "aborting commit due to empty commit message",
+		"commit.invalid_action":    "invalid action",
+		"commit.offline":           "every provider failed, the message was written offline: %v",
+		"commit.partially_staged":  "warning: %s also has unstaged changes that will not be committed",
+		"commit.removes_api":       "warning: %s removes exported %s %s",
+		"commit.breaking":          "warning: the staged changes break the exported API:",
+		"commit.breaking_required": "the staged changes break the exported API, add a %s footer describing it:",
+		"commit.dependency_update": "Dependency update",
+		"commit.saved_message":     "Saved commit message",
+		"commit.saved_args":        "Using the saved git commit options: %s",
+
+		"amend.detected": "Amending HEAD with %v changed file:",
+		"amend.amended":  "amended",
+
+		"reword.original": "Original commit message",
+		"reword.kept":     "kept original message",
+		"reword.nothing":  "nothing to reword",
+		"reword.done":     "reworded %d commit(s)",
+
+		"review.title":       "Review",
+		"review.no_findings": "no findings",
+		"review.findings":    "Review: %d finding(s)",
+		"review.reviewing":   "Reviewing staged changes...",
+
+		"explain.explaining":  "Explaining %s...",
+		"explain.explanation": "Explanation of %s",
+
+		"history.empty":   "no matching history",
+		"history.message": "Message from history",
+		"history.header":  "Time\tOutcome\tModel\tDiff\tMessage",
+
+		"stats.empty":    "no usage recorded yet",
+		"stats.model":    "Model",
+		"stats.repo":     "Repo",
+		"stats.day":      "Day",
+		"stats.header":   "%s\tRequests\tCached\tPrompt\tCompletion\tCost (USD)\tAccepted\tRolled\tCancelled\tFailed",
+		"stats.unpriced": " (+%d unpriced)",
+
+		"cache.cleared":   "cache cleared",
+		"cache.directory": "directory: %s",
+		"cache.enabled":   "enabled:   %v",
+		"cache.entries":   "entries:   %d (%d expired)",
+		"cache.size":      "size:      %s of %d MB",
+		"cache.ttl":       "ttl:       %s",
+		"cache.oldest":    "oldest:    %s",
+		"cache.newest":    "newest:    %s",
+
+		"config.key":           "What is your Gemini API key?",
+		"config.model":         "Which model do you want to use?",
+		"config.emoji":         "Do you want to enable emoji?",
+		"config.custom_url":    "Custom backend connection (leave blank to disable)",
+		"config.i18n":          "i18n",
+		"config.language":      "Language of geminic itself",
+		"config.language_auto": "From LANG",
+		"config.provider":      "Model Provider",
+		"config.offline":       "%s (offline, no model)",
+		"config.saved":         "Configuration saved to %s",
+		"config.valid":         "%s is valid",
+		"config.migrated":      "Upgraded %s to version %d, the previous file is kept at %s",
+		"config.unknown_key":   "warning: unknown key %s in %s is ignored",
+		"config.bad_locale":    "warning: i18n %q is not a known language, using %s",
+
+		"doctor.fix":              "fix: %s",
+		"doctor.summary":          "%d passed, %d warning(s), %d failed",
+		"doctor.git_install":      "install git and make sure it is on your PATH",
+		"doctor.git_upgrade":      "upgrade git to %s or later",
+		"doctor.repo":             "repository",
+		"doctor.not_repo":         "the current directory is not a git repository",
+		"doctor.not_repo_fix":     "run geminic inside the repository you want to commit to",
+		"doctor.in_progress":      "%s has a %s in progress",
+		"doctor.in_progress_fix":  "finish it with git %[1]s --continue or abort it with git %[1]s --abort",
+		"doctor.detached":         "%s is on a detached HEAD",
+		"doctor.detached_fix":     "create a branch with git switch -c <name> so the commits are not lost",
+		"doctor.config":           "config",
+		"doctor.config_missing":   "%s does not exist, the defaults are used",
+		"doctor.config_flag":      "pass the config file with --config or set XDG_CONFIG_HOME",
+		"doctor.config_create":    "run geminic config",
+		"doctor.config_mode":      "%s is readable by others (%v) and holds your key",
+		"doctor.config_chmod":     "chmod 600 %s",
+		"doctor.config_fix":       "run geminic config validate and fix the file with geminic config edit",
+		"doctor.provider":         "provider",
+		"doctor.offline":          "offline, no model is used",
+		"doctor.key":              "key",
+		"doctor.key_missing":      "no API key is configured",
+		"doctor.key_fix":          "run geminic config set key <your key>",
+		"doctor.key_set":          "set",
+		"doctor.endpoint":         "endpoint",
+		"doctor.endpoint_ok":      "%s answered with %d model(s)",
+		"doctor.endpoint_fix":     "check your network and custom_url",
+		"doctor.model":            "model",
+		"doctor.model_missing":    "no model is configured",
+		"doctor.model_unknown":    "%s is not offered by the provider",
+		"doctor.model_fix":        "pick one with geminic models",
+		"doctor.structured":       "structured output",
+		"doctor.structured_ok":    "the model answered with a commit",
+		"doctor.structured_empty": "%s did not answer with a commit",
+		"doctor.structured_fix":   "pick a model that supports JSON schema responses with geminic models",
+	},
+	"zh_CN": {
+		"action.yes":         "是",
+		"action.roll":        "重新生成",
+		"action.refine":      "调整",
+		"action.previous":    "上一个",
+		"action.next":        "下一个",
+		"action.edit":        "编辑",
+		"action.open_editor": "在编辑器中打开",
+		"action.no":          "否",
+
+		"form.confirm":              "这是你想要的吗？",
+		"form.feedback":             "需要怎样修改？",
+		"form.feedback_placeholder": "更简短、提到迁移、scope 应为 api...",
+		"form.reuse":                "使用这条信息提交暂存的更改？",
+		"form.edit":                 "编辑提交信息",
+		"form.edit_confirm":         "确认修改？",
+		"form.select_model":         "选择 Gemini 模型",
+		"form.select_files":         "选择要暂存的文件",
+		"form.pick_history":         "选择要复用的提交信息",
+
+		"editor.help":    "请为您的变更输入提交说明。以 '%s' 开始的行将被忽略，而一个空的提交\n说明将会终止提交。",
+		"editor.changes": "要提交的变更：",
+
+		"status.conflict":   "冲突",
+		"status.untracked":  "未跟踪",
+		"status.deleted":    "已删除",
+		"status.typechange": "类型变更",
+		"status.modified":   "已修改",
+
+		"commit.detected":          "检测到 %v 个暂存的文件：",
+		"commit.empty":             "没有暂存的更改，将创建一个空提交",
+		"commit.generating":        "正在生成提交信息...",
+		"commit.generated":         "生成的提交信息（%s）",
+		"commit.cached":            "缓存",
+		"commit.cancelled":         "已取消",
+		"commit.committed":         "已提交",
+		"commit.aborted":           "提交信息为空，终止提交",
+		"commit.invalid_action":    "无效的操作",
+		"commit.offline":           "所有模型提供方均失败，已离线生成提交信息：%v",
+		"commit.partially_staged":  "警告：%s 还有未暂存的更改，这些更改不会被提交",
+		"commit.removes_api":       "警告：%s 删除了导出的 %s %s",
+		"commit.breaking":          "警告：暂存的更改破坏了导出的 API：",
+		"commit.breaking_required": "暂存的更改破坏了导出的 API，请添加描述它的 %s 脚注：",
+		"commit.dependency_update": "依赖更新",
+		"commit.saved_message":     "已保存的提交信息",
+		"commit.saved_args":        "使用已保存的 git commit 选项: %s",
+
+		"amend.detected": "使用 %v 个变更的文件修改 HEAD：",
+		"amend.amended":  "已修改提交",
+
+		"reword.original": "原提交信息",
+		"reword.kept":     "保留原提交信息",
+		"reword.nothing":  "没有需要改写的提交",
+		"reword.done":     "已改写 %d 个提交",
+
+		"review.title":       "审查",
+		"review.no_findings": "没有发现问题",
+		"review.findings":    "审查：发现 %d 个问题",
+		"review.reviewing":   "正在审查暂存的更改...",
+
+		"explain.explaining":  "正在解释 %s...",
+		"explain.explanation": "%s 的解释",
+
+		"history.empty":   "没有匹配的历史记录",
+		"history.message": "历史中的提交信息",
+		"history.header":  "时间\t结果\t模型\t差异\t提交信息",
+
+		"stats.empty":    "还没有使用记录",
+		"stats.model":    "模型",
+		"stats.repo":     "仓库",
+		"stats.day":      "日期",
+		"stats.header":   "%s\t请求\t缓存\t提示词\t补全\t费用（美元）\t接受率\t重新生成\t取消\t提交失败",
+		"stats.unpriced": "（另有 %d 个未定价）",
+
+		"cache.cleared":   "缓存已清空",
+		"cache.directory": "目录：    %s",
+		"cache.enabled":   "启用：    %v",
+		"cache.entries":   "条目：    %d（%d 个已过期）",
+		"cache.size":      "大小：    %s / %d MB",
+		"cache.ttl":       "有效期：  %s",
+		"cache.oldest":    "最早：    %s",
+		"cache.newest":    "最新：    %s",
+
+		"config.key":           "你的 Gemini API key 是什么？",
+		"config.model":         "你想使用哪个模型？",
+		"config.emoji":         "是否启用 emoji？",
+		"config.custom_url":    "自定义后端地址（留空表示不使用）",
+		"config.i18n":          "提交信息语言（i18n）",
+		"config.language":      "geminic 界面语言",
+		"config.language_auto": "跟随 LANG",
+		"config.provider":      "模型提供方",
+		"config.offline":       "%s（离线，不使用模型）",
+		"config.saved":         "配置已保存到 %s",
+		"config.valid":         "%s 配置有效",
+		"config.migrated":      "已将 %s 升级到版本 %d，原文件保留在 %s",
+		"config.unknown_key":   "警告：%[2]s 中的未知配置项 %[1]s 已被忽略",
+		"config.bad_locale":    "警告：i18n %q 不是已知的语言，将使用 %s",
+
+		"doctor.fix":              "修复：%s",
+		"doctor.summary":          "%d 项通过，%d 项警告，%d 项失败",
+		"doctor.git_install":      "安装 git 并确保它在 PATH 中",
+		"doctor.git_upgrade":      "将 git 升级到 %s 或更高版本",
+		"doctor.repo":             "仓库",
+		"doctor.not_repo":         "当前目录不是 git 仓库",
+		"doctor.not_repo_fix":     "在要提交的仓库中运行 geminic",
+		"doctor.in_progress":      "%s 有进行中的 %s",
+		"doctor.in_progress_fix":  "使用 git %[1]s --continue 完成，或使用 git %[1]s --abort 放弃",
+		"doctor.detached":         "%s 处于分离 HEAD 状态",
+		"doctor.detached_fix":     "使用 git switch -c <名称> 创建分支，以免丢失提交",
+		"doctor.config":           "配置",
+		"doctor.config_missing":   "%s 不存在，使用默认配置",
+		"doctor.config_flag":      "使用 --config 指定配置文件，或设置 XDG_CONFIG_HOME",
+		"doctor.config_create":    "运行 geminic config",
+		"doctor.config_mode":      "%s 可被其他用户读取（%v），而其中保存着你的 key",
+		"doctor.config_chmod":     "chmod 600 %s",
+		"doctor.config_fix":       "运行 geminic config validate，并用 geminic config edit 修正配置文件",
+		"doctor.provider":         "模型提供方",
+		"doctor.offline":          "离线，不使用模型",
+		"doctor.key":              "key",
+		"doctor.key_missing":      "未配置 API key",
+		"doctor.key_fix":          "运行 geminic config set key <你的 key>",
+		"doctor.key_set":          "已设置",
+		"doctor.endpoint":         "接口",
+		"doctor.endpoint_ok":      "%s 返回了 %d 个模型",
+		"doctor.endpoint_fix":     "检查网络和 custom_url",
+		"doctor.model":            "模型",
+		"doctor.model_missing":    "未配置模型",
+		"doctor.model_unknown":    "提供方没有 %s 这个模型",
+		"doctor.model_fix":        "使用 geminic models 选择模型",
+		"doctor.structured":       "结构化输出",
+		"doctor.structured_ok":    "模型返回了提交信息",
+		"doctor.structured_empty": "%s 没有返回提交信息",
+		"doctor.structured_fix":   "使用 geminic models 选择支持 JSON schema 响应的模型",
+	},
+}
@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+const DefaultLanguage = "en_US"
+
+// Locales are the values i18n, the language commit messages are written in,
+// may take.
+var Locales = []string{
+	"ar_SA", "de_DE", "en_GB", "en_US", "es_ES", "fr_FR", "hi_IN", "id_ID",
+	"it_IT", "ja_JP", "ko_KR", "nl_NL", "pl_PL", "pt_BR", "pt_PT", "ru_RU",
+	"th_TH", "tr_TR", "uk_UA", "vi_VN", "zh_CN", "zh_HK", "zh_TW",
+}
+
+var language = detect("")
+
+// Normalize turns the usual spellings of a locale, such as zh-CN or
+// zh_CN.UTF-8, into the form used by Locales.
+func Normalize(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	lang, region, ok := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "_" + strings.ToUpper(region)
+}
+
+// languageNames are the language names earlier versions took as i18n, in
+// English and in the language itself, lower case.
+var languageNames = map[string]string{
+	"arabic": "ar_SA", "العربية": "ar_SA",
+	"german": "de_DE", "deutsch": "de_DE",
+	"english": "en_US",
+	"spanish": "es_ES", "español": "es_ES",
+	"french": "fr_FR", "français": "fr_FR",
+	"hindi": "hi_IN", "हिन्दी": "hi_IN",
+	"indonesian": "id_ID", "bahasa indonesia": "id_ID",
+	"italian": "it_IT", "italiano": "it_IT",
+	"japanese": "ja_JP", "日本語": "ja_JP",
+	"korean": "ko_KR", "한국어": "ko_KR",
+	"dutch": "nl_NL", "nederlands": "nl_NL",
+	"polish": "pl_PL", "polski": "pl_PL",
+	"portuguese": "pt_BR", "português": "pt_BR",
+	"russian": "ru_RU", "русский": "ru_RU",
+	"thai": "th_TH", "ไทย": "th_TH",
+	"turkish": "tr_TR", "türkçe": "tr_TR",
+	"ukrainian": "uk_UA", "українська": "uk_UA",
+	"vietnamese": "vi_VN", "tiếng việt": "vi_VN",
+	"chinese": "zh_CN", "simplified chinese": "zh_CN", "中文": "zh_CN", "简体中文": "zh_CN",
+	"traditional chinese": "zh_TW", "繁體中文": "zh_TW",
+}
+
+// defaultRegions are the locales bare language codes stand for when the
+// language has more than one.
+var defaultRegions = map[string]string{"en": "en_US", "pt": "pt_BR", "zh": "zh_CN"}
+
+// Locale returns the locale value stands for: a locale in any spelling
+// Normalize accepts, a bare language code such as zh, or a language name such
+// as Chinese. ok is false when there is none.
+func Locale(value string) (string, bool) {
+	if locale := Normalize(value); slices.Contains(Locales, locale) {
+		return locale, true
+	}
+	if locale, ok := languageNames[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return locale, true
+	}
+
+	lang := Normalize(strings.TrimSpace(value))
+	if strings.Contains(lang, "_") || lang == "" {
+		return "", false
+	}
+	if locale, ok := defaultRegions[lang]; ok {
+		return locale, true
+	}
+	for _, locale := range Locales {
+		if strings.HasPrefix(locale, lang+"_") {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Validate reports an error when locale is not one of Locales.
+func Validate(locale string) error {
+	if !slices.Contains(Locales, Normalize(locale)) {
+		return fmt.Errorf("unknown locale %q, use one of %s", locale, strings.Join(Locales, ", "))
+	}
+	return nil
+}
+
+// Languages are the languages geminic itself can be shown in.
+func Languages() []string {
+	languages := make([]string, 0, len(catalog))
+	for lang := range catalog {
+		languages = append(languages, lang)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// SetLanguage picks the language of the messages returned by T: lang when
+// geminic has a catalog for it, otherwise the one from the environment.
+func SetLanguage(lang string) {
+	language = detect(lang)
+}
+
+// detect returns the catalog for lang or, when it is empty, for the first of
+// LC_ALL, LC_MESSAGES and LANG that is set, as the C library would pick it.
+// C, POSIX and languages without a catalog get DefaultLanguage.
+func detect(lang string) string {
+	for _, candidate := range []string{lang, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if candidate == "" {
+			continue
+		}
+		candidate = Normalize(candidate)
+		if _, ok := catalog[candidate]; ok {
+			return candidate
+		}
+		// a language without the region, e.g. zh, or a region without a
+		// catalog of its own
+		for known := range catalog {
+			if strings.HasPrefix(known, strings.SplitN(candidate, "_", 2)[0]+"_") {
+				return known
+			}
+		}
+		break
+	}
+	return DefaultLanguage
+}
+
+// T returns the message id in the current language, formatted with args.
+// Messages missing from a catalog fall back to English.
+func T(id string, args ...any) string {
+	message, ok := catalog[language][id]
+	if !ok {
+		if message, ok = catalog[DefaultLanguage][id]; !ok {
+			message = id
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import "testing"
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"zh_CN", "zh_CN", true},
+		{"zh-tw", "zh_TW", true},
+		{"Chinese", "zh_CN", true},
+		{" Traditional Chinese ", "zh_TW", true},
+		{"简体中文", "zh_CN", true},
+		{"日本語", "ja_JP", true},
+		{"zh", "zh_CN", true},
+		{"EN", "en_US", true},
+		{"pt", "pt_BR", true},
+		{"de", "de_DE", true},
+		{"Klingon", "", false},
+		{"xx", "", false},
+		{"zh_XX", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := Locale(tt.value); got != tt.want || ok != tt.ok {
+			t.Errorf("Locale(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"zh_CN", "zh_CN"},
+		{"zh_CN.UTF-8", "zh_CN"},
+		{"zh-cn", "zh_CN"},
+		{"de_DE@euro", "de_DE"},
+		{"ZH", "zh"},
+		{"C", "c"},
+		{"POSIX", "posix"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.locale); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		lcAll   string
+		envLang string
+		want    string
+	}{
+		{"LANG", "", "", "zh_CN.UTF-8", "zh_CN"},
+		{"dashed", "", "", "zh-cn", "zh_CN"},
+		{"language only", "", "", "zh", "zh_CN"},
+		{"LC_ALL first", "", "en_GB.UTF-8", "zh_CN.UTF-8", "en_US"},
+		{"C", "", "C", "zh_CN.UTF-8", DefaultLanguage},
+		{"POSIX", "", "", "POSIX", DefaultLanguage},
+		{"unknown", "", "", "xx_YY", DefaultLanguage},
+		{"nothing set", "", "", "", DefaultLanguage},
+		{"configured", "zh_CN", "C", "", "zh_CN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.envLang)
+			if got := detect(tt.lang); got != tt.want {
+				t.Errorf("detect(%q) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Beriholic/geminic/internal/cache"
+	"github.com/Beriholic/geminic/internal/model/dto"
+)
+
+type cachedResponse struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Text     string `json:"text"`
+}
+
+// CachedLLM answers requests it has already seen for the same provider,
+// model and endpoint from an on-disk cache instead of calling the wrapped
+// LLM.
+type CachedLLM struct {
+	llm      LLM
+	cache    *cache.Cache
+	provider string
+	model    string
+	endpoint string
+}
+
+func WithCache(llm LLM, cache *cache.Cache, provider string, model string, endpoint string) *CachedLLM {
+	return &CachedLLM{llm: llm, cache: cache, provider: provider, model: model, endpoint: endpoint}
+}
+
+// generationSettings describes how a request of kind is sent besides its
+// prompt: the response schema and the sampling settings. It is part of the
+// cache key so that changing them does not serve responses generated under
+// the old ones.
+func generationSettings(kind Kind) string {
+	var schema any
+	switch kind {
+	case KindCommit:
+		schema = dto.GitCommit{}.ToGeminiGenerateStruct()
+	case KindReview:
+		schema = dto.Review{}.ToGeminiGenerateStruct()
+	}
+	data, _ := json.Marshal(schema)
+	return fmt.Sprintf("kind=%d temperature=%v/%v schema=%s", kind, openAITemperature, openAIReviewTemperature, data)
+}
+
+// cacheKey is the key of req in the cache of c.
+func (c *CachedLLM) cacheKey(req *Request) string {
+	parts := []string{c.provider, c.model, c.endpoint, generationSettings(req.Kind), req.Prompt}
+	for _, message := range req.History {
+		parts = append(parts, message.Role, message.Content)
+	}
+	return cache.Key(parts...)
+}
+
+func (c *CachedLLM) Generate(ctx context.Context, req *Request) (*Response, error) {
+	key := c.cacheKey(req)
+
+	if !req.NoCache {
+		if data, ok := c.cache.Get(key); ok {
+			var cached cachedResponse
+			if err := json.Unmarshal(data, &cached); err == nil {
+				if resp, err := parseResponse(req.Kind, cached.Text, cached.Provider, cached.Model); err == nil {
+					if req.OnChunk != nil {
+						req.OnChunk(newChunk(cached.Text, 0))
+					}
+					resp.Cached = true
+					return resp, nil
+				}
+			}
+		}
+	}
+
+	resp, err := c.llm.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(cachedResponse{Provider: resp.Provider, Model: resp.Model, Text: resp.Text})
+	if err == nil {
+		// A cache that cannot be written only costs a future generation.
+		_ = c.cache.Put(key, data)
+	}
+	return resp, nil
+}
+
+func (c *CachedLLM) ModelList(ctx context.Context) ([]string, error) {
+	return c.llm.ModelList(ctx)
+}
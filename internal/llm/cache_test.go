@@ -0,0 +1,34 @@
+package llm
+
+import "testing"
+
+func TestCacheKey(t *testing.T) {
+	req := &Request{Kind: KindCommit, Prompt: "prompt"}
+	base := &CachedLLM{provider: "OpenAI", model: "gpt", endpoint: "https://a.example.com/v1"}
+
+	tests := []struct {
+		name string
+		llm  *CachedLLM
+		req  *Request
+		same bool
+	}{
+		{"same request", base, &Request{Kind: KindCommit, Prompt: "prompt"}, true},
+		{"other endpoint", &CachedLLM{provider: "OpenAI", model: "gpt", endpoint: "https://b.example.com/v1"}, req, false},
+		{"other model", &CachedLLM{provider: "OpenAI", model: "gpt-mini", endpoint: base.endpoint}, req, false},
+		{"other kind", base, &Request{Kind: KindReview, Prompt: "prompt"}, false},
+		{"history", base, &Request{Kind: KindCommit, Prompt: "prompt", History: []Message{{Role: RoleUser, Content: "shorter"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.llm.cacheKey(tt.req) == base.cacheKey(req); got != tt.same {
+				t.Errorf("keys equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestGenerationSettings(t *testing.T) {
+	if generationSettings(KindCommit) == generationSettings(KindReview) {
+		t.Error("commit and review requests share generation settings")
+	}
+}
@@ -0,0 +1,197 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/genai"
+)
+
+var (
+	ErrRateLimited     = errors.New("rate limited")
+	ErrAuthFailed      = errors.New("authentication failed")
+	ErrQuotaExhausted  = errors.New("quota exhausted")
+	ErrModelNotFound   = errors.New("model not found")
+	ErrNotFound        = errors.New("endpoint not found")
+	ErrBlockedBySafety = errors.New("blocked by safety filters")
+	ErrUnavailable     = errors.New("provider unavailable")
+	ErrTimeout         = errors.New("request timed out")
+)
+
+// Error is a provider error classified into one of the Err* kinds above, so
+// callers can decide whether to retry and what to tell the user.
+type Error struct {
+	Kind       error
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Retryable reports whether err is worth retrying with the same provider.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrUnavailable) ||
+		errors.Is(err, ErrTimeout)
+}
+
+// Hint returns an actionable suggestion for a classified error, or "".
+func Hint(err error) string {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return "the provider is rate limiting you, wait a moment or raise retry.max_attempts in the config"
+	case errors.Is(err, ErrAuthFailed):
+		return "check your API key with `geminic config`"
+	case errors.Is(err, ErrQuotaExhausted):
+		return "your quota is exhausted, check the billing of your account or switch model with `geminic models`"
+	case errors.Is(err, ErrModelNotFound):
+		return "the configured model does not exist, pick another one with `geminic models`"
+	case errors.Is(err, ErrNotFound):
+		return "the provider answered 404, check custom_url"
+	case errors.Is(err, ErrBlockedBySafety):
+		return "the response was blocked by safety filters, try a smaller diff or another model"
+	case errors.Is(err, ErrUnavailable):
+		return "the provider is unavailable right now, try again later or check custom_url"
+	case errors.Is(err, ErrTimeout):
+		return "the provider did not answer in time, raise retry.request_timeout in the config"
+	}
+	return ""
+}
+
+// failure is what classifyStatus needs to know about a failed response.
+type failure struct {
+	status  int
+	message string
+	// model is the model that was requested. A 404 is only a missing model
+	// when the provider names it.
+	model string
+	// retryAfter is the delay asked for in a Retry-After header or a
+	// RetryInfo detail.
+	retryAfter time.Duration
+	// dailyQuota is set when a QuotaFailure detail names a per day quota.
+	dailyQuota bool
+}
+
+func classifyStatus(f failure) error {
+	message := strings.ToLower(f.message)
+	switch {
+	case f.status == http.StatusUnauthorized || f.status == http.StatusForbidden:
+		return ErrAuthFailed
+	case f.status == http.StatusNotFound:
+		if f.model != "" && strings.Contains(message, strings.ToLower(f.model)) {
+			return ErrModelNotFound
+		}
+		return ErrNotFound
+	case f.status == http.StatusTooManyRequests:
+		// A per minute limit reads "exceeded your current quota, check
+		// your plan and billing" too, so the text alone says nothing.
+		if f.dailyQuota || strings.Contains(message, "insufficient_quota") {
+			return ErrQuotaExhausted
+		}
+		return ErrRateLimited
+	case f.status == http.StatusRequestTimeout || f.status == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case f.status >= http.StatusInternalServerError:
+		return ErrUnavailable
+	case f.status == http.StatusBadRequest && strings.Contains(message, "api key"):
+		return ErrAuthFailed
+	}
+	return nil
+}
+
+func classifyContextError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return &Error{Kind: ErrTimeout, Err: err}
+	}
+	return err
+}
+
+func classifyGeminiError(ctx context.Context, err error, retryAfter time.Duration, model string) error {
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return classifyContextError(ctx, err)
+	}
+
+	f := geminiFailure(apiErr)
+	f.model = model
+	if retryAfter > 0 {
+		f.retryAfter = retryAfter
+	}
+	kind := classifyStatus(f)
+	if kind == nil {
+		return err
+	}
+	return &Error{Kind: kind, RetryAfter: f.retryAfter, Err: err}
+}
+
+// geminiFailure reads the google.rpc details Gemini attaches to errors:
+// RetryInfo, e.g. {"@type": ".../google.rpc.RetryInfo", "retryDelay": "37s"},
+// and QuotaFailure, whose violations name the quota that was hit, e.g.
+// {"quotaId": "GenerateRequestsPerDayPerProjectPerModel-FreeTier"}.
+func geminiFailure(apiErr genai.APIError) failure {
+	f := failure{status: apiErr.Code, message: apiErr.Status + " " + apiErr.Message}
+	for _, detail := range apiErr.Details {
+		typ, _ := detail["@type"].(string)
+		switch {
+		case strings.HasSuffix(typ, "google.rpc.RetryInfo"):
+			delay, _ := detail["retryDelay"].(string)
+			if d, err := time.ParseDuration(delay); err == nil {
+				f.retryAfter = d
+			}
+		case strings.HasSuffix(typ, "google.rpc.QuotaFailure"):
+			violations, _ := detail["violations"].([]any)
+			for _, violation := range violations {
+				violation, _ := violation.(map[string]any)
+				quotaID, _ := violation["quotaId"].(string)
+				if strings.Contains(quotaID, "PerDay") {
+					f.dailyQuota = true
+				}
+			}
+		}
+	}
+	return f
+}
+
+func classifyOpenAIError(ctx context.Context, err error, retryAfter time.Duration, model string) error {
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+
+	var kind error
+	switch {
+	case errors.As(err, &apiErr):
+		kind = classifyStatus(failure{
+			status:     apiErr.HTTPStatusCode,
+			message:    fmt.Sprintf("%v %s %s", apiErr.Code, apiErr.Type, apiErr.Message),
+			model:      model,
+			retryAfter: retryAfter,
+		})
+		if apiErr.Code == "model_not_found" {
+			kind = ErrModelNotFound
+		}
+	case errors.As(err, &reqErr):
+		kind = classifyStatus(failure{
+			status:     reqErr.HTTPStatusCode,
+			message:    string(reqErr.Body),
+			model:      model,
+			retryAfter: retryAfter,
+		})
+	default:
+		return classifyContextError(ctx, err)
+	}
+
+	if kind == nil {
+		return err
+	}
+	return &Error{Kind: kind, RetryAfter: retryAfter, Err: err}
+}
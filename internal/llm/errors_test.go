@@ -0,0 +1,113 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure failure
+		want    error
+	}{
+		{"unauthorized", failure{status: 401}, ErrAuthFailed},
+		{"forbidden", failure{status: 403}, ErrAuthFailed},
+		{"bad api key", failure{status: 400, message: "API key not valid"}, ErrAuthFailed},
+		{"other bad request", failure{status: 400, message: "invalid argument"}, nil},
+		{"missing model", failure{status: 404, message: "models/gemini-foo is not found for API version v1beta", model: "gemini-foo"}, ErrModelNotFound},
+		{"wrong path", failure{status: 404, message: "404 page not found", model: "gemini-foo"}, ErrNotFound},
+		{"per minute limit mentioning billing", failure{status: 429, message: "You exceeded your current quota, please check your plan and billing details"}, ErrRateLimited},
+		{"rate limit with retry after", failure{status: 429, retryAfter: 30 * time.Second}, ErrRateLimited},
+		{"daily quota", failure{status: 429, message: "quota", dailyQuota: true}, ErrQuotaExhausted},
+		{"openai insufficient quota", failure{status: 429, message: "insufficient_quota"}, ErrQuotaExhausted},
+		{"request timeout", failure{status: 408}, ErrTimeout},
+		{"gateway timeout", failure{status: 504}, ErrTimeout},
+		{"server error", failure{status: 500}, ErrUnavailable},
+		{"overloaded", failure{status: 503}, ErrUnavailable},
+		{"ok", failure{status: 200}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyStatus(tt.failure); got != tt.want {
+				t.Errorf("classifyStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyGeminiError(t *testing.T) {
+	retryInfo := map[string]any{
+		"@type":      "type.googleapis.com/google.rpc.RetryInfo",
+		"retryDelay": "37s",
+	}
+	quotaFailure := func(quotaID string) map[string]any {
+		return map[string]any{
+			"@type":      "type.googleapis.com/google.rpc.QuotaFailure",
+			"violations": []any{map[string]any{"quotaId": quotaID}},
+		}
+	}
+	message := "You exceeded your current quota, please check your plan and billing details."
+
+	tests := []struct {
+		name           string
+		details        []map[string]any
+		retryAfter     time.Duration
+		wantKind       error
+		wantRetryAfter time.Duration
+	}{
+		{"per minute with retry info", []map[string]any{quotaFailure("GenerateRequestsPerMinutePerProjectPerModel-FreeTier"), retryInfo}, 0, ErrRateLimited, 37 * time.Second},
+		{"per day", []map[string]any{quotaFailure("GenerateRequestsPerDayPerProjectPerModel-FreeTier")}, 0, ErrQuotaExhausted, 0},
+		{"no details", nil, 0, ErrRateLimited, 0},
+		{"retry after header wins", []map[string]any{retryInfo}, 5 * time.Second, ErrRateLimited, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyGeminiError(t.Context(), genai.APIError{
+				Code:    429,
+				Status:  "RESOURCE_EXHAUSTED",
+				Message: message,
+				Details: tt.details,
+			}, tt.retryAfter, "gemini-2.0-flash")
+
+			var llmErr *Error
+			if !errors.As(err, &llmErr) {
+				t.Fatalf("classifyGeminiError() = %v, want an *Error", err)
+			}
+			if llmErr.Kind != tt.wantKind {
+				t.Errorf("Kind = %v, want %v", llmErr.Kind, tt.wantKind)
+			}
+			if llmErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", llmErr.RetryAfter, tt.wantRetryAfter)
+			}
+			if Retryable(err) != (tt.wantKind == ErrRateLimited) {
+				t.Errorf("Retryable() = %v", Retryable(err))
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"12", 12 * time.Second},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %v, want up to a minute", date, got)
+	}
+}
@@ -2,14 +2,70 @@ package llm
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/Beriholic/geminic/internal/cache"
 	"github.com/Beriholic/geminic/internal/model"
 	"github.com/Beriholic/geminic/internal/model/model_provider"
 )
 
 func GetLLM(ctx context.Context, cfg *model.Config) (LLM, error) {
-	if cfg.ModelProvider == model_provider.Gemini {
-		return NewGeminiLLM(ctx)
+	var (
+		llm LLM
+		err error
+	)
+	switch cfg.ModelProvider {
+	case model_provider.Heuristic:
+		return NewHeuristicLLM(), nil
+	case model_provider.Gemini:
+		llm, err = NewGeminiLLM(ctx, cfg)
+	default:
+		llm, err = NewOpenAILLM(ctx, cfg)
 	}
-	return NewOpenAILLM(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	llm = WithRetry(llm, cfg.Retry)
+	if cfg.Cache.Enabled {
+		responseCache, err := NewResponseCache(cfg)
+		if err != nil {
+			return nil, err
+		}
+		llm = WithCache(llm, responseCache, cfg.ModelProvider, cfg.Model, cfg.CustomURL)
+	}
+	return llm, nil
+}
+
+func NewResponseCache(cfg *model.Config) (*cache.Cache, error) {
+	dir, err := cache.Dir("responses")
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(dir, cfg.Cache.TTL, int64(cfg.Cache.MaxSizeMB)<<20), nil
+}
+
+// GetFallbackLLMs builds the LLMs of cfg.Fallbacks in order, each through
+// GetLLM with the fallback's provider, model and, if set, key and URL. The
+// primary key is only reused by fallbacks on the same endpoint.
+func GetFallbackLLMs(ctx context.Context, cfg *model.Config) ([]LLM, error) {
+	llms := make([]LLM, 0, len(cfg.Fallbacks))
+	for _, fallback := range cfg.Fallbacks {
+		fallbackCfg := *cfg
+		fallbackCfg.ModelProvider = fallback.ModelProvider
+		fallbackCfg.Model = fallback.Model
+		if fallback.Key != "" || !fallback.SameEndpoint(cfg) {
+			fallbackCfg.Key = fallback.Key
+		}
+		if fallback.CustomURL != "" || fallback.ModelProvider != cfg.ModelProvider {
+			fallbackCfg.CustomURL = fallback.CustomURL
+		}
+
+		llm, err := GetLLM(ctx, &fallbackCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fallback %s/%s: %v", fallback.ModelProvider, fallback.Model, err)
+		}
+		llms = append(llms, llm)
+	}
+	return llms, nil
 }
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
+)
+
+// authServer answers every chat completion and records the key it was sent.
+func authServer(t *testing.T, key *string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*key = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetFallbackLLMsKey(t *testing.T) {
+	var primaryKey, otherKey string
+	primary := authServer(t, &primaryKey)
+	other := authServer(t, &otherKey)
+
+	tests := []struct {
+		name     string
+		fallback model.Fallback
+		got      *string
+		want     string
+	}{
+		{"same endpoint", model.Fallback{ModelProvider: model_provider.OpenAI, Model: "m2"}, &primaryKey, "Bearer primary"},
+		{"same url", model.Fallback{ModelProvider: model_provider.OpenAI, Model: "m2", CustomURL: primary.URL}, &primaryKey, "Bearer primary"},
+		{"own key", model.Fallback{ModelProvider: model_provider.OpenAI, Model: "m2", CustomURL: other.URL, Key: "other"}, &otherKey, "Bearer other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primaryKey, otherKey = "", ""
+			cfg := &model.Config{
+				Key:           "primary",
+				ModelProvider: model_provider.OpenAI,
+				Model:         "m",
+				CustomURL:     primary.URL,
+				Fallbacks:     []model.Fallback{tt.fallback},
+			}
+			llms, err := GetFallbackLLMs(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := llms[0].Generate(context.Background(), &Request{Kind: KindText, Prompt: "p"}); err != nil {
+				t.Fatal(err)
+			}
+			if *tt.got != tt.want {
+				t.Errorf("fallback sent %q, want %q", *tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -2,66 +2,154 @@ package llm
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
 
-	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/model"
 	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
 	"google.golang.org/genai"
 )
 
 type GeminiLLM struct {
 	client *genai.Client
+	model  string
 }
 
-func NewGeminiLLM(ctx context.Context) (*GeminiLLM, error) {
-	if config.Get().CustomURL != "" {
-		genai.SetDefaultBaseURLs(genai.BaseURLParameters{
-			GeminiURL: config.Get().CustomURL,
-			VertexURL: config.Get().CustomURL,
-		})
-	}
+func NewGeminiLLM(ctx context.Context, cfg *model.Config) (*GeminiLLM, error) {
+	// The base URL is set per client rather than through
+	// genai.SetDefaultBaseURLs so fallbacks can point at different endpoints.
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  config.Get().Key,
-		Backend: genai.BackendGeminiAPI,
+		APIKey:      cfg.Key,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  newHTTPClient(),
+		HTTPOptions: genai.HTTPOptions{BaseURL: cfg.CustomURL},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &GeminiLLM{client: client}, nil
+	return &GeminiLLM{client: client, model: cfg.Model}, nil
 }
 
-func (g *GeminiLLM) Generate(ctx context.Context, pmt string) (*dto.GitCommit, error) {
-	geminiConfig := &genai.GenerateContentConfig{
-		ResponseMIMEType: "application/json",
-		ResponseSchema:   dto.GitCommit{}.ToGeminiGenerateStruct(),
+func (g *GeminiLLM) Generate(ctx context.Context, req *Request) (*Response, error) {
+	geminiConfig := &genai.GenerateContentConfig{}
+	switch req.Kind {
+	case KindCommit:
+		geminiConfig.ResponseMIMEType = "application/json"
+		geminiConfig.ResponseSchema = dto.GitCommit{}.ToGeminiGenerateStruct()
+	case KindReview:
+		geminiConfig.ResponseMIMEType = "application/json"
+		geminiConfig.ResponseSchema = dto.Review{}.ToGeminiGenerateStruct()
 	}
 
-	result, err := g.client.Models.GenerateContent(
-		ctx,
-		config.Get().Model,
-		genai.Text(pmt),
-		geminiConfig,
+	ctx, retryAfter := withRetryAfter(ctx)
+
+	var (
+		text      strings.Builder
+		usage     Usage
+		generated int
 	)
+
+	stream, err := g.stream(ctx, req, geminiConfig)
+	if err != nil {
+		return nil, err
+	}
+	for result, err := range stream {
+		if err != nil {
+			return nil, classifyGeminiError(ctx, err, *retryAfter, g.model)
+		}
+		if err := geminiBlocked(result); err != nil {
+			return nil, err
+		}
+
+		text.WriteString(result.Text())
+
+		if result.UsageMetadata != nil {
+			generated = int(result.UsageMetadata.CandidatesTokenCount)
+			usage = Usage{
+				PromptTokens:     int(result.UsageMetadata.PromptTokenCount),
+				CompletionTokens: int(result.UsageMetadata.CandidatesTokenCount + result.UsageMetadata.ThoughtsTokenCount),
+				TotalTokens:      int(result.UsageMetadata.TotalTokenCount),
+			}
+		}
+
+		if req.OnChunk != nil {
+			req.OnChunk(newChunk(text.String(), generated))
+		}
+	}
+
+	resp, err := parseResponse(req.Kind, text.String(), model_provider.Gemini, g.model)
 	if err != nil {
 		return nil, err
 	}
+	resp.Usage = usage
+	return resp, nil
+}
 
-	var gitCommit *dto.GitCommit
+// stream starts a plain generation, or a chat continuing req.History after
+// the prompt when there is one.
+func (g *GeminiLLM) stream(
+	ctx context.Context,
+	req *Request,
+	geminiConfig *genai.GenerateContentConfig,
+) (iter.Seq2[*genai.GenerateContentResponse, error], error) {
+	if len(req.History) == 0 {
+		return g.client.Models.GenerateContentStream(ctx, g.model, genai.Text(req.Prompt), geminiConfig), nil
+	}
+
+	last := req.History[len(req.History)-1]
+	if last.Role != RoleUser {
+		return nil, fmt.Errorf("conversation must end with a user message")
+	}
+
+	history := genai.Text(req.Prompt)
+	for _, message := range req.History[:len(req.History)-1] {
+		role := genai.RoleUser
+		if message.Role == RoleModel {
+			role = genai.RoleModel
+		}
+		history = append(history, &genai.Content{
+			Role:  role,
+			Parts: []*genai.Part{{Text: message.Content}},
+		})
+	}
 
-	if err := json.Unmarshal([]byte(result.Text()), &gitCommit); err != nil {
+	chat, err := g.client.Chats.Create(ctx, g.model, geminiConfig, history)
+	if err != nil {
 		return nil, err
 	}
+	return chat.SendMessageStream(ctx, genai.Part{Text: last.Content}), nil
+}
 
-	return gitCommit, nil
+func geminiBlocked(result *genai.GenerateContentResponse) error {
+	if result.PromptFeedback != nil && result.PromptFeedback.BlockReason != "" {
+		return &Error{
+			Kind: ErrBlockedBySafety,
+			Err:  fmt.Errorf("prompt blocked: %s %s", result.PromptFeedback.BlockReason, result.PromptFeedback.BlockReasonMessage),
+		}
+	}
+	for _, candidate := range result.Candidates {
+		switch candidate.FinishReason {
+		case genai.FinishReasonSafety, genai.FinishReasonProhibitedContent, genai.FinishReasonBlocklist, genai.FinishReasonSPII:
+			return &Error{
+				Kind: ErrBlockedBySafety,
+				Err:  fmt.Errorf("response blocked: %s", candidate.FinishReason),
+			}
+		}
+	}
+	return nil
 }
 
 func (g *GeminiLLM) ModelList(ctx context.Context) ([]string, error) {
+	ctx, retryAfter := withRetryAfter(ctx)
+
 	var models []string
 	iter := g.client.Models.All(ctx)
 	for model, err := range iter {
 		if err != nil {
-			return nil, err
+			return nil, classifyGeminiError(ctx, err, *retryAfter, g.model)
 		}
 		models = append(models, model.Name)
 	}
@@ -0,0 +1,216 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
+)
+
+const HeuristicModel = "offline"
+
+// HeuristicLLM writes commit messages without a model: the type comes from
+// the kind of files changed, the scope from their common directory and the
+// subject from the changed symbols or files. It only handles commits.
+type HeuristicLLM struct{}
+
+func NewHeuristicLLM() *HeuristicLLM {
+	return &HeuristicLLM{}
+}
+
+func (h *HeuristicLLM) Generate(ctx context.Context, req *Request) (*Response, error) {
+	if req.Kind != KindCommit || req.Commit == nil {
+		return nil, fmt.Errorf("the offline generator can only write commit messages")
+	}
+
+	paths := changedPaths(req.Commit)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("the offline generator needs changed files")
+	}
+
+	commit := &dto.GitCommit{
+		Typ:   heuristicType(req.Commit, paths),
+		Scope: heuristicScope(paths),
+		Msg:   heuristicSubject(req.Commit, paths),
+	}
+
+	text, err := json.Marshal(commit)
+	if err != nil {
+		return nil, err
+	}
+	if req.OnChunk != nil {
+		req.OnChunk(Chunk{Text: string(text)})
+	}
+
+	return &Response{
+		Provider: model_provider.Heuristic,
+		Model:    HeuristicModel,
+		Text:     string(text),
+		Commit:   commit,
+	}, nil
+}
+
+func (h *HeuristicLLM) ModelList(ctx context.Context) ([]string, error) {
+	return []string{HeuristicModel}, nil
+}
+
+func changedPaths(commit *dto.CommitDTO) []string {
+	if len(commit.Changes) == 0 {
+		return commit.Files
+	}
+	paths := make([]string, len(commit.Changes))
+	for idx, change := range commit.Changes {
+		paths[idx] = change.Path
+	}
+	return paths
+}
+
+var ciPaths = []string{
+	".github/workflows/", ".gitlab-ci.yml", ".circleci/", ".travis.yml", "Jenkinsfile",
+	"azure-pipelines.yml", "bitbucket-pipelines.yml", ".drone.yml", ".buildkite/", ".woodpecker",
+}
+
+func isCIPath(file string) bool {
+	for _, ci := range ciPaths {
+		if strings.HasPrefix(file, ci) || file == ci {
+			return true
+		}
+	}
+	return false
+}
+
+func isDocPath(file string) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".md", ".mdx", ".rst", ".adoc", ".txt":
+		return true
+	}
+	return strings.HasPrefix(file, "docs/")
+}
+
+func isTestPath(file string) bool {
+	return strings.HasSuffix(file, "_test.go") || strings.Contains(file, "testdata/")
+}
+
+func allPaths(paths []string, match func(string) bool) bool {
+	for _, file := range paths {
+		if !match(file) {
+			return false
+		}
+	}
+	return true
+}
+
+func heuristicType(commit *dto.CommitDTO, paths []string) string {
+	switch {
+	case allPaths(paths, isTestPath):
+		return "test"
+	case allPaths(paths, isDocPath):
+		return "docs"
+	case allPaths(paths, isCIPath):
+		return "ci"
+	case len(commit.Bumps) > 0 && allPaths(paths, isBuildPath):
+		return "build"
+	}
+
+	for _, symbol := range commit.Symbols {
+		if symbol.Change == dto.SymbolAdded {
+			return "feat"
+		}
+	}
+	for _, change := range commit.Changes {
+		if change.Status == "A" {
+			return "feat"
+		}
+	}
+	if len(commit.Symbols) > 0 {
+		return "refactor"
+	}
+	return "chore"
+}
+
+func isBuildPath(file string) bool {
+	switch path.Base(file) {
+	case "go.mod", "go.sum", "package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Makefile", "Dockerfile":
+		return true
+	}
+	return false
+}
+
+// heuristicScope is the last element of the directory all paths share, or
+// "" when they only share the repository root.
+func heuristicScope(paths []string) string {
+	common := path.Dir(paths[0])
+	for _, file := range paths[1:] {
+		for common != "." && !strings.HasPrefix(file, common+"/") {
+			common = path.Dir(common)
+		}
+	}
+	if common == "." || common == "/" {
+		return ""
+	}
+	return path.Base(common)
+}
+
+var symbolVerbs = []struct {
+	change string
+	verb   string
+}{
+	{dto.SymbolAdded, "add"},
+	{dto.SymbolModified, "update"},
+	{dto.SymbolRemoved, "remove"},
+}
+
+func heuristicSubject(commit *dto.CommitDTO, paths []string) string {
+	for _, sv := range symbolVerbs {
+		var names []string
+		for _, symbol := range commit.Symbols {
+			if symbol.Change == sv.change && !strings.HasSuffix(symbol.File, "_test.go") {
+				names = append(names, symbol.Name)
+			}
+		}
+		if len(names) > 0 {
+			return truncateSubject(sv.verb + " " + listNames(names))
+		}
+	}
+
+	if len(commit.Changes) == 1 {
+		change := commit.Changes[0]
+		switch change.Status[0] {
+		case 'A':
+			return truncateSubject("add " + change.Path)
+		case 'D':
+			return truncateSubject("remove " + change.Path)
+		case 'R':
+			return truncateSubject(fmt.Sprintf("rename %s to %s", change.OldPath, change.Path))
+		}
+	}
+
+	names := make([]string, len(paths))
+	for idx, file := range paths {
+		names[idx] = path.Base(file)
+	}
+	return truncateSubject("update " + listNames(names))
+}
+
+// listNames joins up to three names, summarizing the rest as a count.
+func listNames(names []string) string {
+	switch {
+	case len(names) == 1:
+		return names[0]
+	case len(names) <= 3:
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:2], ", "), len(names)-2)
+}
+
+func truncateSubject(subject string) string {
+	const maxLen = 60
+	if runes := []rune(subject); len(runes) > maxLen {
+		return string(runes[:maxLen-3]) + "..."
+	}
+	return subject
+}
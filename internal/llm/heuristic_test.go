@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+)
+
+func TestHeuristicType(t *testing.T) {
+	tests := []struct {
+		name   string
+		commit dto.CommitDTO
+		paths  []string
+		want   string
+	}{
+		{"tests only", dto.CommitDTO{}, []string{"a_test.go", "testdata/in.json"}, "test"},
+		{"docs only", dto.CommitDTO{}, []string{"README.md", "docs/setup.html"}, "docs"},
+		{"ci only", dto.CommitDTO{}, []string{".github/workflows/go.yml", ".gitlab-ci.yml"}, "ci"},
+		{"dependency bump", dto.CommitDTO{Bumps: []string{"bump x from v1 to v2"}}, []string{"go.mod", "go.sum"}, "build"},
+		{"manifest without bumps", dto.CommitDTO{}, []string{"go.mod"}, "chore"},
+		{
+			name:   "added symbol",
+			commit: dto.CommitDTO{Symbols: []dto.SymbolChange{{Name: "A", Change: dto.SymbolModified}, {Name: "B", Change: dto.SymbolAdded}}},
+			paths:  []string{"a.go"},
+			want:   "feat",
+		},
+		{"added file", dto.CommitDTO{Changes: []dto.FileChange{{Status: "A", Path: "run.sh"}}}, []string{"run.sh"}, "feat"},
+		{
+			name:   "modified symbols",
+			commit: dto.CommitDTO{Symbols: []dto.SymbolChange{{Name: "A", Change: dto.SymbolModified}}},
+			paths:  []string{"a.go"},
+			want:   "refactor",
+		},
+		{"mixed", dto.CommitDTO{}, []string{"a_test.go", "README.md"}, "chore"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heuristicType(&tt.commit, tt.paths); got != tt.want {
+				t.Errorf("heuristicType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicScope(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"main.go"}, ""},
+		{[]string{"internal/llm/heuristic.go"}, "llm"},
+		{[]string{"internal/llm/a.go", "internal/llm/prompt/p.go"}, "llm"},
+		{[]string{"internal/llm/a.go", "internal/ui/b.go"}, "internal"},
+		{[]string{"internal/llm/a.go", "cmd/root.go"}, ""},
+		{[]string{"internal/llm/a.go", "internal/llmx/b.go"}, "internal"},
+	}
+
+	for _, tt := range tests {
+		if got := heuristicScope(tt.paths); got != tt.want {
+			t.Errorf("heuristicScope(%q) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestHeuristicSubject(t *testing.T) {
+	tests := []struct {
+		name   string
+		commit dto.CommitDTO
+		paths  []string
+		want   string
+	}{
+		{
+			name: "added symbols first",
+			commit: dto.CommitDTO{Symbols: []dto.SymbolChange{
+				{File: "a.go", Name: "Old", Change: dto.SymbolRemoved},
+				{File: "a.go", Name: "New", Change: dto.SymbolAdded},
+				{File: "a.go", Name: "Make", Change: dto.SymbolAdded},
+			}},
+			paths: []string{"a.go"},
+			want:  "add New and Make",
+		},
+		{
+			name: "test symbols are left out",
+			commit: dto.CommitDTO{Symbols: []dto.SymbolChange{
+				{File: "a_test.go", Name: "TestA", Change: dto.SymbolAdded},
+				{File: "a.go", Name: "A", Change: dto.SymbolModified},
+			}},
+			paths: []string{"a.go", "a_test.go"},
+			want:  "update A",
+		},
+		{
+			name: "many symbols",
+			commit: dto.CommitDTO{Symbols: []dto.SymbolChange{
+				{File: "a.go", Name: "A", Change: dto.SymbolRemoved},
+				{File: "a.go", Name: "B", Change: dto.SymbolRemoved},
+				{File: "a.go", Name: "C", Change: dto.SymbolRemoved},
+				{File: "a.go", Name: "D", Change: dto.SymbolRemoved},
+			}},
+			paths: []string{"a.go"},
+			want:  "remove A, B and 2 more",
+		},
+		{"added file", dto.CommitDTO{Changes: []dto.FileChange{{Status: "A", Path: "run.sh"}}}, []string{"run.sh"}, "add run.sh"},
+		{"deleted file", dto.CommitDTO{Changes: []dto.FileChange{{Status: "D", Path: "old.txt"}}}, []string{"old.txt"}, "remove old.txt"},
+		{
+			name:   "renamed file",
+			commit: dto.CommitDTO{Changes: []dto.FileChange{{Status: "R100", OldPath: "a.md", Path: "docs/a.md"}}},
+			paths:  []string{"docs/a.md"},
+			want:   "rename a.md to docs/a.md",
+		},
+		{"modified files", dto.CommitDTO{}, []string{"cmd/root.go", "README.md", "go.mod"}, "update root.go, README.md and go.mod"},
+		{
+			name:   "long subject",
+			commit: dto.CommitDTO{Changes: []dto.FileChange{{Status: "A", Path: "internal/" + strings.Repeat("x", 80) + ".go"}}},
+			paths:  []string{"internal/" + strings.Repeat("x", 80) + ".go"},
+			want:   "add internal/" + strings.Repeat("x", 44) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heuristicSubject(&tt.commit, tt.paths); got != tt.want {
+				t.Errorf("heuristicSubject() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicGenerate(t *testing.T) {
+	h := NewHeuristicLLM()
+
+	if _, err := h.Generate(context.Background(), &Request{Kind: KindText, Prompt: "x"}); err == nil {
+		t.Error("Generate(text) succeeded")
+	}
+	if _, err := h.Generate(context.Background(), &Request{Kind: KindCommit, Commit: &dto.CommitDTO{}}); err == nil {
+		t.Error("Generate() without changed files succeeded")
+	}
+
+	resp, err := h.Generate(context.Background(), &Request{
+		Kind:   KindCommit,
+		Commit: &dto.CommitDTO{Files: []string{"docs/usage.md"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := resp.Commit; c.Typ != "docs" || c.Scope != "docs" || c.Msg != "update usage.md" {
+		t.Errorf("Generate() = %+v", c)
+	}
+}
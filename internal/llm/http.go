@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type retryAfterKey struct{}
+
+// withRetryAfter returns a context whose HTTP responses record their
+// Retry-After header into the returned duration. Neither SDK exposes response
+// headers on its errors, so this is the only place to pick it up.
+func withRetryAfter(ctx context.Context) (context.Context, *time.Duration) {
+	retryAfter := new(time.Duration)
+	return context.WithValue(ctx, retryAfterKey{}, retryAfter), retryAfter
+}
+
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	retryAfter, ok := req.Context().Value(retryAfterKey{}).(*time.Duration)
+	if ok && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		*retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	return resp, nil
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &retryAfterTransport{base: http.DefaultTransport},
+	}
+}
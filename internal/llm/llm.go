@@ -2,11 +2,128 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
 
 	"github.com/Beriholic/geminic/internal/model/dto"
 )
 
+type Kind int
+
+const (
+	// KindCommit asks for a structured dto.GitCommit.
+	KindCommit Kind = iota
+	// KindText asks for free-form text.
+	KindText
+	// KindReview asks for a structured dto.Review.
+	KindReview
+)
+
+const (
+	RoleUser  string = "user"
+	RoleModel string = "model"
+)
+
+// Message is one turn of a conversation that continues after the prompt.
+type Message struct {
+	Role    string
+	Content string
+}
+
+type Request struct {
+	Kind   Kind
+	Prompt string
+	// History continues the conversation started by Prompt with alternating
+	// model and user turns. When set it must end with a user turn.
+	History []Message
+	// OnChunk, if set, is called with the text received so far while the
+	// response is being streamed.
+	OnChunk func(Chunk)
+	// NoCache skips looking the request up in the response cache. The fresh
+	// response is still stored.
+	NoCache bool
+	// Commit is what Prompt was built from, for generators that work on the
+	// changes themselves rather than on the prompt.
+	Commit *dto.CommitDTO
+}
+
+type Chunk struct {
+	Text string
+	// Tokens is the number of tokens generated so far, as reported by the
+	// provider, or estimated from Text until it reports usage.
+	Tokens    int
+	Estimated bool
+}
+
+// newChunk reports text with the completion tokens the provider reported so
+// far, estimating them from text when it has not reported any yet.
+func newChunk(text string, completionTokens int) Chunk {
+	if completionTokens > 0 {
+		return Chunk{Text: text, Tokens: completionTokens}
+	}
+	return Chunk{Text: text, Tokens: estimateTokens(text), Estimated: true}
+}
+
+// estimateTokens guesses the number of tokens in text: about four characters
+// per token for ASCII and one per character otherwise, e.g. for CJK.
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+type Response struct {
+	Provider string
+	Model    string
+	Cached   bool
+	Usage    Usage
+
+	Text   string
+	Commit *dto.GitCommit
+	Review *dto.Review
+
+	// FallbackReason is the error of the last provider when the response
+	// comes from the offline generator instead.
+	FallbackReason error
+}
+
 type LLM interface {
-	Generate(ctx context.Context, prompt string) (*dto.GitCommit, error)
+	Generate(ctx context.Context, req *Request) (*Response, error)
 	ModelList(ctx context.Context) ([]string, error)
 }
+
+func parseResponse(kind Kind, text string, provider string, model string) (*Response, error) {
+	resp := &Response{Provider: provider, Model: model, Text: text}
+
+	switch kind {
+	case KindCommit:
+		if err := json.Unmarshal([]byte(text), &resp.Commit); err != nil {
+			return nil, fmt.Errorf("json: %v err: %v", text, err)
+		}
+		if resp.Commit == nil {
+			return nil, fmt.Errorf("Blank repley")
+		}
+	case KindReview:
+		if err := json.Unmarshal([]byte(text), &resp.Review); err != nil {
+			return nil, fmt.Errorf("json: %v err: %v", text, err)
+		}
+		if resp.Review == nil {
+			return nil, fmt.Errorf("Blank repley")
+		}
+	}
+
+	return resp, nil
+}
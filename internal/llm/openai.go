@@ -2,80 +2,188 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
-	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/model"
 	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
 	"github.com/sashabaranov/go-openai"
 	"github.com/sashabaranov/go-openai/jsonschema"
 )
 
 type OpenAILLM struct {
 	client *openai.Client
+	model  string
 }
 
-func NewOpenAILLM(ctx context.Context) (*OpenAILLM, error) {
-	apiConfig := openai.DefaultConfig(config.Get().Key)
+func NewOpenAILLM(ctx context.Context, cfg *model.Config) (*OpenAILLM, error) {
+	apiConfig := openai.DefaultConfig(cfg.Key)
+	apiConfig.HTTPClient = newHTTPClient()
 
-	if config.Get().CustomURL != "" {
-		apiConfig.BaseURL = config.Get().CustomURL
+	if cfg.CustomURL != "" {
+		apiConfig.BaseURL = cfg.CustomURL
 	}
 
 	client := openai.NewClientWithConfig(apiConfig)
-	return &OpenAILLM{client: client}, nil
+	return &OpenAILLM{client: client, model: cfg.Model}, nil
 }
 
-func (o *OpenAILLM) Generate(ctx context.Context, prompt string) (*dto.GitCommit, error) {
-	var gitCommit dto.GitCommit
+func (o *OpenAILLM) Generate(ctx context.Context, req *Request) (*Response, error) {
+	request, err := o.buildRequest(req)
+	if err != nil {
+		return nil, err
+	}
 
-	schema, err := jsonschema.GenerateSchemaForType(gitCommit)
+	ctx, retryAfter := withRetryAfter(ctx)
+
+	stream, err := o.client.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, classifyOpenAIError(ctx, err, *retryAfter, o.model)
+	}
+	defer stream.Close()
+
+	var (
+		text  strings.Builder
+		usage Usage
+	)
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, classifyOpenAIError(ctx, err, *retryAfter, o.model)
+		}
+
+		if resp.Usage != nil {
+			usage = Usage{
+				PromptTokens:     resp.Usage.PromptTokens,
+				CompletionTokens: resp.Usage.CompletionTokens,
+				TotalTokens:      resp.Usage.TotalTokens,
+			}
+		}
+
+		// with IncludeUsage the usage arrives in a last chunk without choices
+		if len(resp.Choices) > 0 {
+			if resp.Choices[0].FinishReason == openai.FinishReasonContentFilter {
+				return nil, &Error{
+					Kind: ErrBlockedBySafety,
+					Err:  fmt.Errorf("response blocked: %s", resp.Choices[0].FinishReason),
+				}
+			}
+			text.WriteString(resp.Choices[0].Delta.Content)
+		}
+
+		if req.OnChunk != nil {
+			req.OnChunk(newChunk(text.String(), usage.CompletionTokens))
+		}
+	}
+
+	resp, err := parseResponse(req.Kind, text.String(), model_provider.OpenAI, o.model)
 	if err != nil {
 		return nil, err
 	}
+	resp.Usage = usage
+	return resp, nil
+}
+
+const (
+	openAITemperature       = 0.75
+	openAIReviewTemperature = 0.2
+)
+
+func (o *OpenAILLM) buildRequest(req *Request) (openai.ChatCompletionRequest, error) {
+	request := openai.ChatCompletionRequest{
+		Model:       o.model,
+		Temperature: openAITemperature,
+		Stream:      true,
+		StreamOptions: &openai.StreamOptions{
+			IncludeUsage: true,
+		},
+	}
 
-	resp, err := o.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model:       config.Get().Model,
-		Temperature: 0.75,
-		Messages: []openai.ChatCompletionMessage{
+	switch req.Kind {
+	case KindCommit:
+		schema, err := jsonschema.GenerateSchemaForType(dto.GitCommit{})
+		if err != nil {
+			return request, err
+		}
+		request.Messages = []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleSystem,
-				Content: prompt,
+				Content: req.Prompt,
 			},
 			{
 				Role:    openai.ChatMessageRoleUser,
 				Content: "Start writing a Git commit",
 			},
-		},
-		ResponseFormat: &openai.ChatCompletionResponseFormat{
+		}
+		request.ResponseFormat = &openai.ChatCompletionResponseFormat{
 			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
 			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
 				Name:   "result of git commit",
 				Schema: schema,
 				Strict: true,
 			},
-		},
-	})
-	if err != nil {
-		return nil, err
+		}
+	case KindReview:
+		schema, err := jsonschema.GenerateSchemaForType(dto.Review{})
+		if err != nil {
+			return request, err
+		}
+		request.Temperature = openAIReviewTemperature
+		request.Messages = []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: req.Prompt,
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: "Start reviewing the staged changes",
+			},
+		}
+		request.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "result_of_code_review",
+				Schema: schema,
+				Strict: true,
+			},
+		}
+	default:
+		request.Messages = []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: req.Prompt,
+			},
+		}
 	}
 
-	if resp.Choices == nil {
-		return nil, fmt.Errorf("Blank repley")
+	for _, message := range req.History {
+		role := openai.ChatMessageRoleUser
+		if message.Role == RoleModel {
+			role = openai.ChatMessageRoleAssistant
+		}
+		request.Messages = append(request.Messages, openai.ChatCompletionMessage{
+			Role:    role,
+			Content: message.Content,
+		})
 	}
 
-	content := resp.Choices[0].Message.Content
-	err = schema.Unmarshal(content, &gitCommit)
-	if err != nil {
-		return nil, fmt.Errorf("json: %v err: %v", content, err)
-	}
-	return &gitCommit, nil
+	return request, nil
 }
 
 func (o *OpenAILLM) ModelList(ctx context.Context) ([]string, error) {
+	ctx, retryAfter := withRetryAfter(ctx)
+
 	var models []string
 	_models, err := o.client.ListModels(ctx)
 	if err != nil {
-		return nil, err
+		return nil, classifyOpenAIError(ctx, err, *retryAfter, o.model)
 	}
 
 	for _, model := range _models.Models {
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"fix", 1},
+		{"feat: add x", 3},
+		{"添加语言选择", 6},
+		{"fix: 修复", 4},
+	}
+
+	for _, tt := range tests {
+		if got := estimateTokens(tt.text); got != tt.want {
+			t.Errorf("estimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestOpenAIStreamTokens checks that the token count is estimated while the
+// text streams in and replaced by the usage OpenAI sends last.
+func TestOpenAIStreamTokens(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, data := range []string{
+			`{"choices":[{"index":0,"delta":{"content":"fix: "}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":"handle empty diff"}}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":42,"completion_tokens":5,"total_tokens":47}}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+	}))
+	defer srv.Close()
+
+	o, err := NewOpenAILLM(context.Background(), &model.Config{Key: "k", Model: "m", CustomURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var chunks []Chunk
+	resp, err := o.Generate(context.Background(), &Request{
+		Kind:    KindText,
+		Prompt:  "explain",
+		OnChunk: func(chunk Chunk) { chunks = append(chunks, chunk) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Chunk{
+		{Text: "fix: ", Tokens: 2, Estimated: true},
+		{Text: "fix: handle empty diff", Tokens: 6, Estimated: true},
+		{Text: "fix: handle empty diff", Tokens: 5},
+	}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("chunks = %+v\nwant %+v", chunks, want)
+	}
+	if resp.Usage.PromptTokens != 42 || resp.Usage.CompletionTokens != 5 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
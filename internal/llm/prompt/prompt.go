@@ -2,7 +2,9 @@ package prompt
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/Beriholic/geminic/internal/config"
 	"github.com/Beriholic/geminic/internal/model/dto"
@@ -41,19 +43,54 @@ func (p *Prompt) Build(commitDTO *dto.CommitDTO) string {
 		AddRule().
 		AddCommitType().
 		AddCommitEmoji().
-		AddCommitInfo(commitDTO.Commit, commitDTO.Diff, commitDTO.Files).
-		AddI18n().
+		AddCommitInfo(commitDTO.Commit, commitDTO.Diff, commitDTO.Files, commitDTO.Changes).
+		AddSymbolChanges(commitDTO.Symbols).
+		AddBreakingChanges(commitDTO.Breaking).
+		AddDependencyBumps(commitDTO.Bumps).
+		AddCommitI18n().
 		AddOutputTemplateStruct()
 
 	return p.Basic + "\n" + strings.Join(p.Struct, "\n")
 }
 
+func (p *Prompt) BuildExplain(history string) string {
+	p.Basic = "You now need to explain to a new team member what the following git history changes, please follow the rules"
+
+	p.
+		AddExplainRule().
+		AddGitHistory(history).
+		AddI18n()
+
+	return p.Basic + "\n" + strings.Join(p.Struct, "\n")
+}
+
+func (p *Prompt) BuildReview(commitDTO *dto.CommitDTO) string {
+	if commitDTO == nil {
+		return ""
+	}
+
+	p.Basic = "You now need to review the staged changes before they are committed, please follow the rules"
+
+	p.
+		AddReviewRule().
+		AddCommitInfo("", commitDTO.Diff, commitDTO.Files, commitDTO.Changes).
+		AddI18n().
+		AddReviewOutputTemplateStruct()
+
+	return p.Basic + "\n" + strings.Join(p.Struct, "\n")
+}
+
 func (p *Prompt) AddStruct(prompt string) *Prompt {
 	p.Struct = append(p.Struct, prompt)
 	return p
 }
 
 func (p *Prompt) AddRule() *Prompt {
+	// a bilingual message asks for the translation, see AddCommitI18n
+	exclude := "Exclude anything unnecessary such as translation."
+	if config.Get().Bilingual.Secondary != "" {
+		exclude = "Exclude anything unnecessary."
+	}
 	prompt := `
 <Rule>
 - Write in first-person singular present tense
@@ -61,7 +98,7 @@ func (p *Prompt) AddRule() *Prompt {
 - Output only the commit message without any explanations
 - Commit message should starts with lowercase letter.
 - Commit message must be a maximum of 72 characters.
-- Exclude anything unnecessary such as translation. Your entire response will be passed directly into git commit.
+- ` + exclude + ` Your entire response will be passed directly into git commit.
 - Commit Message without subject
 </Rule>
 `
@@ -113,12 +150,16 @@ func (p *Prompt) AddCommitInfo(
 	commit string,
 	diff string,
 	files []string,
+	changes []dto.FileChange,
 ) *Prompt {
 	userInput := ""
 	if commit != "" {
 		userInput = fmt.Sprintf(`<UserInput> %s (write on this basis) </UserInput>`, commit)
 	}
 	fileChanged := fmt.Sprintf(`<FilesChanged> %s </-changed>`, strings.Join(files, ", "))
+	if len(changes) > 0 {
+		fileChanged = fmt.Sprintf("<FilesChanged>\n%s</FilesChanged>", formatFileChanges(changes))
+	}
 	codeDiff := fmt.Sprintf(`<CodeDiff> %s </CodeDiff>`, diff)
 
 	p.AddStructStart("CommitInfo")
@@ -131,6 +172,59 @@ func (p *Prompt) AddCommitInfo(
 	return p
 }
 
+func (p *Prompt) AddExplainRule() *Prompt {
+	prompt := `
+<Rule>
+- Use plain language that someone new to the codebase can follow
+- Start with the intent: what problem the change solves and why it was made
+- Then list the affected areas (packages, modules, commands, config) and how they changed
+- Then point out the risky parts: behaviour changes, edge cases, missing tests, migrations
+- When several commits are given, explain how the code evolved across them in order
+- Do not repeat the diff line by line
+</Rule>
+`
+	return p.AddStruct(prompt)
+}
+
+func (p *Prompt) AddGitHistory(history string) *Prompt {
+	p.AddStructStart("GitHistory")
+	p.AddStruct(history)
+	p.AddStructEnd("GitHistory")
+	return p
+}
+
+func (p *Prompt) AddReviewRule() *Prompt {
+	prompt := `
+<Rule>
+- Only report real problems in the added or changed lines: bugs, security issues, data races, leaked resources, missing error handling, debug leftovers
+- Do not report style preferences or praise
+- Use severity "high" only for problems that must be fixed before committing
+- Use the line number of the new version of the file, or 0 when the finding is not tied to a line
+- Return an empty list of findings when nothing is wrong
+</Rule>
+`
+	return p.AddStruct(prompt)
+}
+
+// AddCommitI18n is AddI18n for commit messages, which may be bilingual.
+func (p *Prompt) AddCommitI18n() *Prompt {
+	bilingual := config.Get().Bilingual
+	if bilingual.Secondary == "" {
+		return p.AddI18n()
+	}
+
+	prompt := fmt.Sprintf(
+		"You need to write msg in %s language, and put the same msg translated into %s language in translation",
+		bilingual.Primary,
+		bilingual.Secondary,
+	)
+
+	p.AddStructStart("I18n")
+	p.AddStruct(prompt)
+	p.AddStructEnd("I18n")
+	return p
+}
+
 func (p *Prompt) AddI18n() *Prompt {
 	prompt := fmt.Sprintf("You need to write it in %s language", config.Get().I18n)
 
@@ -148,8 +242,108 @@ func (p *Prompt) AddOutputTemplateStruct() *Prompt {
 			"typ": "(required)The type of git commit",
 			"msg": "(required)The subject of git commit"
 			"scope": "(optinal)The scope of git commit",
-			"emoji": "(optinal)The emoji of git commit" 
+			"emoji": "(optinal)The emoji of git commit",
+			"breaking": "(optinal)What breaks for users, only when BreakingChanges are given",
+			"translation": "(optinal)The msg translated, only when asked for a translation"
 		}`)
 	p.AddStructEnd("OutputTempalte")
 	return p
 }
+
+func (p *Prompt) AddReviewOutputTemplateStruct() *Prompt {
+	p.AddStructStart("OutputTempalte")
+	p.AddStruct(`
+		Output only the following JSON structure, without any additional content
+		{
+			"findings": [
+				{
+					"file": "(required)The path of the file",
+					"line": (required)The line number, 0 if unknown,
+					"severity": "(required)high, medium or low",
+					"message": "(required)What is wrong and how to fix it"
+				}
+			]
+		}`)
+	p.AddStructEnd("OutputTempalte")
+	return p
+}
+
+// Refine is the follow-up message asking for a reworked commit message.
+func Refine(feedback string) string {
+	return fmt.Sprintf(`<Feedback> %s </Feedback>
+Rewrite the commit message following the feedback above, keep following the rules and output only the same JSON structure`, feedback)
+}
+
+func (p *Prompt) AddSymbolChanges(symbols []dto.SymbolChange) *Prompt {
+	if len(symbols) == 0 {
+		return p
+	}
+
+	var sb strings.Builder
+	for _, symbol := range symbols {
+		fmt.Fprintf(&sb, "%s: %s %s %s", symbol.File, symbol.Change, symbol.Kind, symbol.Name)
+		if symbol.RemovesAPI() {
+			sb.WriteString(" (removes exported API)")
+		}
+		sb.WriteString("\n")
+	}
+
+	prompt := fmt.Sprintf(`<GoSymbolChanges>
+%s</GoSymbolChanges>
+- Use these Go declarations to name what the commit changes
+- Mention removed exported API in the commit message`, sb.String())
+	return p.AddStruct(prompt)
+}
+
+func (p *Prompt) AddBreakingChanges(breaking []string) *Prompt {
+	if len(breaking) == 0 {
+		return p
+	}
+
+	prompt := fmt.Sprintf(`<BreakingChanges>
+%s
+</BreakingChanges>
+- These changes break code that uses the exported API
+- Fill "breaking" with a short description of what breaks and how to migrate`, strings.Join(breaking, "\n"))
+	return p.AddStruct(prompt)
+}
+
+func (p *Prompt) AddDependencyBumps(bumps []string) *Prompt {
+	if len(bumps) == 0 {
+		return p
+	}
+
+	prompt := fmt.Sprintf(`<DependencyBumps>
+%s
+</DependencyBumps>
+- These dependency updates are part of the commit, mention them only if they are its main purpose`, strings.Join(bumps, "\n"))
+	return p.AddStruct(prompt)
+}
+
+// formatFileChanges renders changes as a table with git's status letter (with
+// the similarity of renames and copies), added and deleted lines, and the path.
+func formatFileChanges(changes []dto.FileChange) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "status\t+\t-\tpath")
+	for _, change := range changes {
+		additions, deletions := strconv.Itoa(change.Additions), strconv.Itoa(change.Deletions)
+		if change.Binary {
+			additions, deletions = "bin", "bin"
+		}
+
+		path := change.Path
+		if change.OldPath != "" {
+			path = change.OldPath + " -> " + change.Path
+		}
+		if change.ModeChanged() {
+			path += fmt.Sprintf(" (mode %s -> %s)", change.OldMode, change.NewMode)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.Status, additions, deletions, path)
+	}
+
+	w.Flush()
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/model"
+)
+
+// RetryLLM retries retryable provider errors of the wrapped LLM with
+// exponential backoff and jitter, within per-request and total timeouts.
+type RetryLLM struct {
+	llm LLM
+	cfg model.RetryConfig
+}
+
+func WithRetry(llm LLM, cfg model.RetryConfig) *RetryLLM {
+	return &RetryLLM{llm: llm, cfg: cfg}
+}
+
+func (r *RetryLLM) Generate(ctx context.Context, req *Request) (*Response, error) {
+	return retry(ctx, r.cfg, func(ctx context.Context) (*Response, error) {
+		return r.llm.Generate(ctx, req)
+	})
+}
+
+func (r *RetryLLM) ModelList(ctx context.Context) ([]string, error) {
+	return retry(ctx, r.cfg, r.llm.ModelList)
+}
+
+func retry[T any](ctx context.Context, cfg model.RetryConfig, fn func(context.Context) (T, error)) (T, error) {
+	if cfg.TotalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.TotalTimeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := attemptWithTimeout(ctx, cfg.RequestTimeout, fn)
+		if err == nil || !Retryable(err) || attempt >= cfg.MaxAttempts {
+			return result, err
+		}
+
+		delay := backoff(cfg, attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return result, err
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return result, err
+		}
+	}
+}
+
+func attemptWithTimeout[T any](ctx context.Context, timeout time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := fn(attemptCtx)
+	if err != nil && attemptCtx.Err() != nil && ctx.Err() == nil {
+		return result, &Error{Kind: ErrTimeout, Err: err}
+	}
+	return result, err
+}
+
+// backoff returns the delay before the next attempt: the provider's
+// Retry-After when it sent one, otherwise an exponential delay with jitter.
+func backoff(cfg model.RetryConfig, attempt int, err error) time.Duration {
+	if retryAfter := retryAfterOf(err); retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := cfg.InitialBackoff << (attempt - 1)
+	if delay <= 0 || (cfg.MaxBackoff > 0 && delay > cfg.MaxBackoff) {
+		delay = cfg.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func retryAfterOf(err error) time.Duration {
+	var llmErr *Error
+	if errors.As(err, &llmErr) {
+		return llmErr.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/model"
+)
+
+func TestBackoff(t *testing.T) {
+	cfg := model.RetryConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		name     string
+		attempt  int
+		err      error
+		min, max time.Duration
+	}{
+		{"first attempt", 1, ErrRateLimited, 500 * time.Millisecond, time.Second},
+		{"doubles", 3, ErrRateLimited, 2 * time.Second, 4 * time.Second},
+		{"capped", 10, ErrRateLimited, 2500 * time.Millisecond, 5 * time.Second},
+		{"overflow is capped", 80, ErrRateLimited, 2500 * time.Millisecond, 5 * time.Second},
+		{"retry after wins", 1, &Error{Kind: ErrRateLimited, RetryAfter: 42 * time.Second}, 42 * time.Second, 42 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				if got := backoff(cfg, tt.attempt, tt.err); got < tt.min || got > tt.max {
+					t.Fatalf("backoff() = %v, want between %v and %v", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	cfg := model.RetryConfig{MaxAttempts: 3}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{"success", []error{nil}, 1, nil},
+		{"retries rate limits", []error{&Error{Kind: ErrRateLimited}, nil}, 2, nil},
+		{"gives up after max attempts", []error{&Error{Kind: ErrUnavailable}, &Error{Kind: ErrUnavailable}, &Error{Kind: ErrUnavailable}}, 3, ErrUnavailable},
+		{"does not retry exhausted quota", []error{&Error{Kind: ErrQuotaExhausted}, nil}, 1, ErrQuotaExhausted},
+		{"does not retry a missing model", []error{&Error{Kind: ErrModelNotFound}, nil}, 1, ErrModelNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			_, err := retry(context.Background(), cfg, func(context.Context) (string, error) {
+				err := tt.errs[attempts]
+				attempts++
+				return "", err
+			})
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("retry() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
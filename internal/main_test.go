@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain points geminic's config, data and cache directories at a
+// temporary home, so tests never read or write the user's.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "geminic-home")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	os.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	os.Unsetenv("GIT_DIR")
+	os.Unsetenv("GIT_WORK_TREE")
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// initRepo creates an empty git repository and makes it the working
+// directory for the rest of the test.
+func initRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v %s", err, out)
+	}
+	t.Chdir(dir)
+	runGit(t, "config", "user.name", "test")
+	runGit(t, "config", "user.email", "test@example.com")
+	runGit(t, "config", "commit.gpgsign", "false")
+}
+
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitFiles writes files and commits them with message.
+func commitFiles(t *testing.T, message string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		writeFile(t, name, content)
+	}
+	runGit(t, "add", "-A")
+	runGit(t, "commit", "-q", "--allow-empty", "-m", message)
+}
@@ -1,25 +1,131 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
 	value_utils "github.com/Beriholic/geminic/internal/utils"
+	"github.com/Beriholic/geminic/internal/xdg"
 	"github.com/spf13/viper"
 )
 
-const configFilePath = "$HOME/.config/geminic/config.toml"
+// configFile is the path given with --config, if any.
+var configFile string
 
-var v *viper.Viper
+// envPrefix is the prefix of the environment variables that override
+// settings, e.g. GEMINIC_MODEL or GEMINIC_RETRY_MAX_ATTEMPTS.
+const envPrefix = "GEMINIC"
 
 type Config struct {
+	Version int `mapstructure:"version"`
+
+	// Key, Model, ModelProvider and CustomURL are those of the profile named
+	// by Profile.
+	Profile  string             `mapstructure:"profile"`
+	Profiles map[string]Profile `mapstructure:"profiles"`
+
 	Key           string `mapstructure:"key"`
 	Model         string `mapstructure:"model"`
 	Emoji         bool   `mapstructure:"emoji"`
 	CustomURL     string `mapstructure:"custom_url"`
 	I18n          string `mapstructure:"i18n"`
+	Language      string `mapstructure:"language"`
+	ModelProvider string `mapstructure:"model_provider"`
+
+	ProtectedUpstreams []string `mapstructure:"protected_upstreams"`
+	Review             bool     `mapstructure:"review"`
+	// APIPackages are the package directories whose exported API is checked
+	// for breaking changes, as dir or dir/... patterns. Empty means every
+	// package but main.
+	APIPackages []string `mapstructure:"api_packages"`
+
+	Bilingual BilingualConfig `mapstructure:"bilingual"`
+
+	CommitArgs []string     `mapstructure:"commit_args"`
+	Repos      []RepoConfig `mapstructure:"repos"`
+
+	Retry     RetryConfig `mapstructure:"retry"`
+	Fallbacks []Fallback  `mapstructure:"fallbacks"`
+	Cache     CacheConfig `mapstructure:"cache"`
+	Prices    []Price     `mapstructure:"prices"`
+}
+
+// DefaultProfile is the profile used when none is selected.
+const DefaultProfile = "default"
+
+// Profile is a provider, model and key to generate with.
+type Profile struct {
+	Key           string `mapstructure:"key"`
+	Model         string `mapstructure:"model"`
+	ModelProvider string `mapstructure:"model_provider"`
+	CustomURL     string `mapstructure:"custom_url"`
+}
+
+const (
+	// BilingualLayoutBody puts the translation in the body, after a blank line.
+	BilingualLayoutBody = "body"
+	// BilingualLayoutTrailer adds the translation as a Subject-<locale>
+	// trailer, e.g. Subject-zh-CN.
+	BilingualLayoutTrailer = "trailer"
+)
+
+var BilingualLayouts = []string{BilingualLayoutBody, BilingualLayoutTrailer}
+
+// BilingualConfig writes commit messages in Primary with a translation into
+// Secondary. An empty Secondary disables it.
+type BilingualConfig struct {
+	Primary   string `mapstructure:"primary"`
+	Secondary string `mapstructure:"secondary"`
+	Layout    string `mapstructure:"layout"`
+}
+
+// RepoConfig holds settings that only apply to the repository at Path.
+type RepoConfig struct {
+	Path       string   `mapstructure:"path"`
+	CommitArgs []string `mapstructure:"commit_args"`
+}
+
+// Price is what a model costs, in USD per million tokens.
+type Price struct {
+	Model  string  `mapstructure:"model"`
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+}
+
+type CacheConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	TTL       time.Duration `mapstructure:"ttl"`
+	MaxSizeMB int           `mapstructure:"max_size_mb"`
+}
+
+// Fallback is a provider and model tried, in order, when the primary one
+// fails. An empty CustomURL reuses the primary URL only when the provider is
+// the same, and an empty Key reuses the primary key only when both are.
+type Fallback struct {
 	ModelProvider string `mapstructure:"model_provider"`
+	Model         string `mapstructure:"model"`
+	Key           string `mapstructure:"key"`
+	CustomURL     string `mapstructure:"custom_url"`
+}
+
+// SameEndpoint reports whether f talks to the same provider and URL as the
+// primary model of c, so that it can share its key.
+func (f Fallback) SameEndpoint(c *Config) bool {
+	return f.ModelProvider == c.ModelProvider && (f.CustomURL == "" || f.CustomURL == c.CustomURL)
+}
+
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	TotalTimeout   time.Duration `mapstructure:"total_timeout"`
 }
 type LocalConfig struct {
 	Emoji bool   `mapstructure:"emoji"`
@@ -30,59 +136,278 @@ func (c *Config) UseCustom() bool {
 	return c.CustomURL != ""
 }
 
+// CommitArgsFor returns the default git commit args for the repository at
+// root: the global commit_args followed by those of a matching repos entry.
+func (c *Config) CommitArgsFor(root string) []string {
+	args := append([]string{}, c.CommitArgs...)
+	root = cleanPath(root)
+	for _, repo := range c.Repos {
+		if cleanPath(repo.Path) == root {
+			args = append(args, repo.CommitArgs...)
+		}
+	}
+	return args
+}
+
+func cleanPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Clean(path)
+}
+
 func (c *Config) Load() error {
 	v, err := initViper()
 	if err != nil {
 		return err
 	}
+	if err := c.read(v); err != nil {
+		return err
+	}
+	for _, key := range unknownKeys(v.AllSettings(), "") {
+		fmt.Fprintln(os.Stderr, i18n.T("config.unknown_key", key, v.ConfigFileUsed()))
+	}
+	return c.Validate()
+}
 
-	c.Key = v.GetString("key")
-	c.Model = v.GetString("model")
+func (c *Config) read(v *viper.Viper) error {
+	c.Version = value_utils.GetOrDefault(v.GetInt("version"), CurrentVersion)
+	c.Profile = value_utils.GetStrngOrDefault(v.GetString("profile"), DefaultProfile)
+	c.Key = v.GetString(profileKey(c.Profile, "key"))
+	c.Model = v.GetString(profileKey(c.Profile, "model"))
+	c.ModelProvider = v.GetString(profileKey(c.Profile, "model_provider"))
+	c.CustomURL = v.GetString(profileKey(c.Profile, "custom_url"))
 	c.Emoji = v.GetBool("emoji")
-	c.CustomURL = v.GetString("custom_url")
-	c.I18n = value_utils.GetStrngOrDefault(v.GetString("i18n"), "en_US")
-	c.ModelProvider = v.GetString("model_provider")
+	c.I18n = i18n.Normalize(value_utils.GetStrngOrDefault(v.GetString("i18n"), "en_US"))
+	c.Language = v.GetString("language")
+	c.Bilingual.Primary = i18n.Normalize(value_utils.GetStrngOrDefault(v.GetString("bilingual.primary"), c.I18n))
+	c.Bilingual.Secondary = i18n.Normalize(v.GetString("bilingual.secondary"))
+	c.Bilingual.Layout = value_utils.GetStrngOrDefault(v.GetString("bilingual.layout"), BilingualLayoutBody)
+	c.ProtectedUpstreams = v.GetStringSlice("protected_upstreams")
+	c.Review = v.GetBool("review")
+	c.APIPackages = v.GetStringSlice("api_packages")
+	c.CommitArgs = v.GetStringSlice("commit_args")
+	c.Retry.MaxAttempts = value_utils.GetOrDefault(v.GetInt("retry.max_attempts"), 4)
+	c.Retry.InitialBackoff = value_utils.GetOrDefault(v.GetDuration("retry.initial_backoff"), time.Second)
+	c.Retry.MaxBackoff = value_utils.GetOrDefault(v.GetDuration("retry.max_backoff"), 30*time.Second)
+	c.Retry.RequestTimeout = value_utils.GetOrDefault(v.GetDuration("retry.request_timeout"), 2*time.Minute)
+	c.Retry.TotalTimeout = value_utils.GetOrDefault(v.GetDuration("retry.total_timeout"), 5*time.Minute)
+	c.Cache.Enabled = !v.IsSet("cache.enabled") || v.GetBool("cache.enabled")
+	c.Cache.TTL = value_utils.GetOrDefault(v.GetDuration("cache.ttl"), 24*time.Hour)
+	c.Cache.MaxSizeMB = value_utils.GetOrDefault(v.GetInt("cache.max_size_mb"), 10)
+	if err := v.UnmarshalKey("profiles", &c.Profiles); err != nil {
+		return fmt.Errorf("failed to read profiles: %v", err)
+	}
+	if err := v.UnmarshalKey("fallbacks", &c.Fallbacks); err != nil {
+		return fmt.Errorf("failed to read fallbacks: %v", err)
+	}
+	if err := v.UnmarshalKey("prices", &c.Prices); err != nil {
+		return fmt.Errorf("failed to read prices: %v", err)
+	}
+	if err := v.UnmarshalKey("repos", &c.Repos); err != nil {
+		return fmt.Errorf("failed to read repos: %v", err)
+	}
 	return nil
 }
 
+// Validate checks every setting of c and reports all invalid ones at once.
+func (c *Config) Validate() error {
+	var errs []error
+	for _, setting := range Settings {
+		if setting.Validate == nil {
+			continue
+		}
+		if err := setting.Validate(setting.Get(c)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %v", setting.Key, err))
+		}
+	}
+	if _, ok := c.Profiles[c.Profile]; !ok && c.Profile != DefaultProfile {
+		errs = append(errs, fmt.Errorf("invalid profile: %q is not defined under profiles", c.Profile))
+	}
+	for name, profile := range c.Profiles {
+		if err := validateProvider(profile.ModelProvider); err != nil {
+			errs = append(errs, fmt.Errorf("invalid profiles.%s.model_provider: %v", name, err))
+		}
+		if err := validateURL(profile.CustomURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid profiles.%s.custom_url: %v", name, err))
+		}
+	}
+	for i, fallback := range c.Fallbacks {
+		if err := validateProvider(fallback.ModelProvider); err != nil {
+			errs = append(errs, fmt.Errorf("invalid fallbacks[%d].model_provider: %v", i, err))
+		}
+		if err := validateURL(fallback.CustomURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid fallbacks[%d].custom_url: %v", i, err))
+		}
+		if fallback.Key == "" && fallback.ModelProvider != model_provider.Heuristic && !fallback.SameEndpoint(c) {
+			errs = append(errs, fmt.Errorf("invalid fallbacks[%d].key: required when the provider or custom_url differs from the primary one", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Save writes the settings that differ from the ones in effect, leaving the
+// others, and anything geminic config does not know about, untouched. Values
+// that come from the environment are not written unless they were changed.
 func (c *Config) Save() error {
-	expandedPath := os.ExpandEnv(configFilePath)
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
 	v, err := initViper()
 	if err != nil {
 		return err
 	}
+	var current Config
+	if err := current.read(v); err != nil {
+		return err
+	}
+
+	values := make(map[string]any)
+	for _, setting := range Settings {
+		value := setting.Get(c)
+		if setting.Format(value, false) != setting.Format(setting.Get(&current), false) {
+			values[setting.Key] = setting.fileValue(value)
+		}
+	}
+	if err := writeKeys(values); err != nil {
+		return err
+	}
 
-	v.Set("key", c.Key)
-	v.Set("model", c.Model)
-	v.Set("emoji", c.Emoji)
-	v.Set("custom_url", c.CustomURL)
-	v.Set("i18n", c.I18n)
-	v.Set("model_provider", c.ModelProvider)
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("config.saved", path))
+	return nil
+}
 
-	if err := v.WriteConfigAs(expandedPath); err != nil {
-		return fmt.Errorf("failed to write config file: %v", err)
+// SetValue validates args as the value of the setting key and writes only
+// that key to the config file.
+func SetValue(key string, args []string) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	value, err := setting.Parse(args)
+	if err != nil {
+		return err
+	}
+	if setting.Validate != nil {
+		if err := setting.Validate(value); err != nil {
+			return fmt.Errorf("invalid %s: %v", setting.Key, err)
+		}
+	}
+	return writeKeys(map[string]any{setting.Key: setting.fileValue(value)})
+}
+
+// SetConfigPath makes geminic use the config file at path instead of the one
+// in the config directory.
+func SetConfigPath(path string) {
+	configFile = path
+}
+
+func ConfigPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
 	}
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
 
-	fmt.Printf("Configuration saved to %s\n", expandedPath)
+func writeKeys(values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	v, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	profile := activeProfile(v)
+	for key, value := range values {
+		if setting, err := LookupSetting(key); err == nil {
+			key = setting.fileKey(profile)
+		}
+		v.Set(key, value)
+	}
+	if !v.IsSet("version") {
+		v.Set("version", CurrentVersion)
+	}
+
+	path := v.ConfigFileUsed()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	// The file holds the key. The permissions only apply when it is created,
+	// an existing file keeps its own.
+	v.SetConfigPermissions(0600)
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
 	return nil
 }
 
-func initViper() (*viper.Viper, error) {
-	if v != nil {
-		return v, nil
+// readConfigFile reads only the config file, without the environment, so
+// that writing it back does not add keys it did not have. A missing file is
+// read as an empty one.
+func readConfigFile() (*viper.Viper, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
 	}
 
 	v := viper.New()
-	expandedPath := os.ExpandEnv(configFilePath)
+	v.SetConfigFile(path)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return v, nil
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	return migrate(v)
+}
 
-	configDir := filepath.Dir(expandedPath)
-	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
-		return nil, fmt.Errorf("failed to create config directory: %v", err)
+// activeProfile is the profile selected by GEMINIC_PROFILE or the config file
+// v, which is read without the environment.
+func activeProfile(v *viper.Viper) string {
+	if profile := os.Getenv(envPrefix + "_PROFILE"); profile != "" {
+		return profile
 	}
+	return value_utils.GetStrngOrDefault(v.GetString("profile"), DefaultProfile)
+}
 
-	v.SetConfigFile(expandedPath)
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to red config file: %v", err)
+func profileKey(profile string, key string) string {
+	return "profiles." + profile + "." + key
+}
+
+// initViper reads the config file with the GEMINIC_* environment variables
+// overriding it.
+func initViper() (*viper.Viper, error) {
+	v, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := activeProfile(v)
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for _, setting := range Settings {
+		// Profile settings live under profiles.<name> but keep the short
+		// variable, GEMINIC_KEY rather than GEMINIC_PROFILES_DEFAULT_KEY.
+		env := envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(setting.Key, ".", "_"))
+		if err := v.BindEnv(setting.fileKey(profile), env); err != nil {
+			return nil, fmt.Errorf("failed to bind %s: %v", setting.Key, err)
+		}
 	}
 	return v, nil
 }
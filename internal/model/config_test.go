@@ -0,0 +1,174 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model/model_provider"
+	"github.com/spf13/viper"
+)
+
+func TestReadBilingualLayout(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{"", BilingualLayoutBody},
+		{BilingualLayoutTrailer, BilingualLayoutTrailer},
+	}
+
+	for _, tt := range tests {
+		v := viper.New()
+		if tt.layout != "" {
+			v.Set("bilingual.layout", tt.layout)
+		}
+		var c Config
+		if err := c.read(v); err != nil {
+			t.Fatal(err)
+		}
+		if c.Bilingual.Layout != tt.want {
+			t.Errorf("layout %q read as %q, want %q", tt.layout, c.Bilingual.Layout, tt.want)
+		}
+	}
+}
+
+func TestValidateFallbackKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback Fallback
+		wantErr  bool
+	}{
+		{"same provider", Fallback{ModelProvider: model_provider.OpenAI, Model: "m2"}, false},
+		{"same url", Fallback{ModelProvider: model_provider.OpenAI, CustomURL: "https://llm.example.com/v1"}, false},
+		{"other url", Fallback{ModelProvider: model_provider.OpenAI, CustomURL: "https://other.example.com/v1"}, true},
+		{"other url with key", Fallback{ModelProvider: model_provider.OpenAI, CustomURL: "https://other.example.com/v1", Key: "k"}, false},
+		{"other provider", Fallback{ModelProvider: model_provider.Gemini}, true},
+		{"other provider with key", Fallback{ModelProvider: model_provider.Gemini, Key: "k"}, false},
+		{"heuristic", Fallback{ModelProvider: model_provider.Heuristic}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{
+				ModelProvider: model_provider.OpenAI,
+				CustomURL:     "https://llm.example.com/v1",
+				Fallbacks:     []Fallback{tt.fallback},
+			}
+			err := c.Validate()
+			if got := err != nil && strings.Contains(err.Error(), "fallbacks[0].key"); got != tt.wantErr {
+				t.Errorf("Validate() = %v, want a key error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommitArgsFor(t *testing.T) {
+	root := t.TempDir()
+	c := Config{
+		CommitArgs: []string{"--no-verify"},
+		Repos: []RepoConfig{
+			{Path: root, CommitArgs: []string{"-S"}},
+			{Path: root + "/other", CommitArgs: []string{"--signoff"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		root string
+		want []string
+	}{
+		{"matching repo", root, []string{"--no-verify", "-S"}},
+		{"trailing slash", root + "/", []string{"--no-verify", "-S"}},
+		{"other repo", t.TempDir(), []string{"--no-verify"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.CommitArgsFor(tt.root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitArgsFor(%q) = %q, want %q", tt.root, got, tt.want)
+			}
+		})
+	}
+	if len(c.CommitArgs) != 1 {
+		t.Errorf("CommitArgsFor changed commit_args to %q", c.CommitArgs)
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	xdgDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	t.Cleanup(func() { SetConfigPath("") })
+
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(xdgDir, "geminic", "config.toml"); path != want {
+		t.Errorf("ConfigPath() = %q, want %q", path, want)
+	}
+
+	flagPath := filepath.Join(t.TempDir(), "other.toml")
+	SetConfigPath(flagPath)
+	if path, err := ConfigPath(); err != nil || path != flagPath {
+		t.Errorf("ConfigPath() with --config = %q, %v, want %q", path, err, flagPath)
+	}
+}
+
+func TestSaveNewFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c Config
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	c.Key = "secret"
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("new config file mode = %o, want 600", mode)
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("version = 2\n\n[profiles.default]\nmodel = \"file-model\"\nkey = \"k\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	SetConfigPath(path)
+	t.Cleanup(func() { SetConfigPath("") })
+	t.Setenv("GEMINIC_MODEL", "env-model")
+	t.Setenv("GEMINIC_RETRY_MAX_ATTEMPTS", "2")
+
+	var c Config
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Model != "env-model" || c.Retry.MaxAttempts != 2 || c.Key != "k" {
+		t.Errorf("Load() = model %q, retry.max_attempts %d, key %q", c.Model, c.Retry.MaxAttempts, c.Key)
+	}
+
+	// overrides are not written back when something else changes
+	c.Review = true
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	written := readConfig(t, path)
+	if model := written.GetString("profiles.default.model"); model != "file-model" {
+		t.Errorf("written model = %q, want file-model", model)
+	}
+	if written.IsSet("retry.max_attempts") || !written.GetBool("review") {
+		t.Errorf("written retry.max_attempts %v, review %v", written.Get("retry.max_attempts"), written.Get("review"))
+	}
+}
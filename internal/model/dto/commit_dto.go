@@ -4,4 +4,11 @@ type CommitDTO struct {
 	Commit string   `json:"commit,omitempty"`
 	Diff   string   `json:"diff,omitempty"`
 	Files  []string `json:"files,omitempty"`
+
+	Changes []FileChange   `json:"changes,omitempty"`
+	Symbols []SymbolChange `json:"symbols,omitempty"`
+	// Breaking lists the incompatible changes to exported Go APIs.
+	Breaking []string `json:"breaking,omitempty"`
+	// Bumps lists the dependency version changes, e.g. "bump x from v1 to v2".
+	Bumps []string `json:"bumps,omitempty"`
 }
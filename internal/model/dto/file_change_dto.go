@@ -0,0 +1,20 @@
+package dto
+
+// FileChange describes one file of a diff as reported by git diff --raw and
+// --numstat with rename and copy detection.
+type FileChange struct {
+	Status    string `json:"status"`
+	Path      string `json:"path"`
+	OldPath   string `json:"old_path,omitempty"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary,omitempty"`
+	OldMode   string `json:"old_mode,omitempty"`
+	NewMode   string `json:"new_mode,omitempty"`
+}
+
+// ModeChanged reports whether the file mode changed, e.g. it became
+// executable. Added and deleted files only have one mode.
+func (f FileChange) ModeChanged() bool {
+	return f.OldMode != f.NewMode && f.OldMode != "000000" && f.NewMode != "000000"
+}
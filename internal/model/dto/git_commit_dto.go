@@ -3,8 +3,10 @@ package dto
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/model"
 	"google.golang.org/genai"
 )
 
@@ -13,19 +15,64 @@ type GitCommit struct {
 	Emoji string `json:"emoji" desc:"emoji of commit" required:"false"`
 	Scope string `json:"scope" desc:"scope of commit" required:"false"`
 	Msg   string `json:"msg" desc:"msg of commit" required:"true"`
+
+	Breaking    string `json:"breaking" desc:"what the commit breaks for users of the API" required:"false"`
+	Translation string `json:"translation" desc:"msg translated into the secondary language" required:"false"`
 }
 
 func (g GitCommit) String() string {
+	bang := ""
+	if g.Breaking != "" {
+		bang = "!"
+	}
+	message := g.subject(bang)
+
+	var footers []string
+	if g.Breaking != "" {
+		footers = append(footers, BreakingChangeFooter+" "+g.Breaking)
+	}
+
+	bilingual := config.Get().Bilingual
+	if translation := strings.TrimSpace(g.Translation); translation != "" && bilingual.Secondary != "" {
+		if bilingual.Layout == model.BilingualLayoutTrailer {
+			// trailer tokens may only hold letters, digits and dashes
+			footers = append(footers, fmt.Sprintf("Subject-%s: %s", strings.ReplaceAll(bilingual.Secondary, "_", "-"), translation))
+		} else {
+			message += "\n\n" + translation
+		}
+	}
+
+	if len(footers) > 0 {
+		message += "\n\n" + strings.Join(footers, "\n")
+	}
+	return message
+}
+
+func (g GitCommit) subject(bang string) string {
 	if g.Scope != "" {
 		if g.Emoji != "" {
-			return fmt.Sprintf("%s %s(%s): %s", g.Typ, g.Emoji, g.Scope, g.Msg)
+			return fmt.Sprintf("%s %s(%s)%s: %s", g.Typ, g.Emoji, g.Scope, bang, g.Msg)
 		}
-		return fmt.Sprintf("%s(%s): %s", g.Typ, g.Scope, g.Msg)
+		return fmt.Sprintf("%s(%s)%s: %s", g.Typ, g.Scope, bang, g.Msg)
 	}
 	if g.Emoji != "" {
-		return fmt.Sprintf("%s %s: %s", g.Typ, g.Emoji, g.Msg)
+		return fmt.Sprintf("%s %s%s: %s", g.Typ, g.Emoji, bang, g.Msg)
+	}
+	return fmt.Sprintf("%s%s: %s", g.Typ, bang, g.Msg)
+}
+
+const BreakingChangeFooter = "BREAKING CHANGE:"
+
+// HasBreakingChangeFooter reports whether message has a BREAKING CHANGE
+// footer, in either of the spellings conventional commits allows.
+func HasBreakingChangeFooter(message string) bool {
+	lines := strings.Split(message, "\n")
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, BreakingChangeFooter) || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return true
+		}
 	}
-	return fmt.Sprintf("%s: %s", g.Typ, g.Msg)
+	return false
 }
 
 func (g GitCommit) ToGeminiGenerateStruct() *genai.Schema {
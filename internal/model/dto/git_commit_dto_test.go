@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/model"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "geminic-dto")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestGitCommitString(t *testing.T) {
+	commit := GitCommit{Typ: "feat", Scope: "ui", Msg: "add language selection", Translation: "添加语言选择"}
+	breaking := commit
+	breaking.Breaking = "Config.Lang was removed"
+
+	tests := []struct {
+		name      string
+		commit    GitCommit
+		secondary string
+		layout    string
+		want      string
+	}{
+		{
+			name:   "plain",
+			commit: GitCommit{Typ: "fix", Msg: "handle empty diff"},
+			want:   "fix: handle empty diff",
+		},
+		{
+			name:   "emoji",
+			commit: GitCommit{Typ: "fix", Emoji: "🐛", Scope: "git", Msg: "handle empty diff"},
+			want:   "fix 🐛(git): handle empty diff",
+		},
+		{
+			name:   "translation without a secondary language",
+			commit: commit,
+			want:   "feat(ui): add language selection",
+		},
+		{
+			name:      "body",
+			commit:    commit,
+			secondary: "zh_CN",
+			layout:    model.BilingualLayoutBody,
+			want:      "feat(ui): add language selection\n\n添加语言选择",
+		},
+		{
+			name:      "trailer",
+			commit:    commit,
+			secondary: "zh_CN",
+			layout:    model.BilingualLayoutTrailer,
+			want:      "feat(ui): add language selection\n\nSubject-zh-CN: 添加语言选择",
+		},
+		{
+			name:   "breaking",
+			commit: GitCommit{Typ: "feat", Msg: "drop Lang", Breaking: "Config.Lang was removed"},
+			want:   "feat!: drop Lang\n\nBREAKING CHANGE: Config.Lang was removed",
+		},
+		{
+			name:      "breaking with trailer",
+			commit:    breaking,
+			secondary: "zh_CN",
+			layout:    model.BilingualLayoutTrailer,
+			want:      "feat(ui)!: add language selection\n\nBREAKING CHANGE: Config.Lang was removed\nSubject-zh-CN: 添加语言选择",
+		},
+		{
+			name:      "breaking with body",
+			commit:    breaking,
+			secondary: "zh_CN",
+			layout:    model.BilingualLayoutBody,
+			want:      "feat(ui)!: add language selection\n\n添加语言选择\n\nBREAKING CHANGE: Config.Lang was removed",
+		},
+	}
+
+	cfg := config.Get()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Bilingual.Secondary = tt.secondary
+			cfg.Bilingual.Layout = tt.layout
+
+			got := tt.commit.String()
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if HasBreakingChangeFooter(got) != (tt.commit.Breaking != "") {
+				t.Errorf("HasBreakingChangeFooter(%q) = %v", got, !(tt.commit.Breaking != ""))
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"google.golang.org/genai"
+)
+
+const (
+	SeverityHigh   string = "high"
+	SeverityMedium string = "medium"
+	SeverityLow    string = "low"
+)
+
+type ReviewFinding struct {
+	File     string `json:"file" desc:"path of the file the finding is about" required:"true"`
+	Line     int    `json:"line" desc:"line in the new version of the file, 0 if it is not tied to a line" required:"true"`
+	Severity string `json:"severity" desc:"high for bugs or security issues, medium for likely mistakes, low for nits" enum:"high,medium,low" required:"true"`
+	Message  string `json:"message" desc:"what is wrong and how to fix it" required:"true"`
+}
+
+type Review struct {
+	Findings []ReviewFinding `json:"findings" desc:"findings of the review, empty when nothing is wrong" required:"true"`
+}
+
+func (r Review) Count(severity string) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+func (r Review) ToGeminiGenerateStruct() *genai.Schema {
+	finding := &genai.Schema{
+		Type:       genai.TypeObject,
+		Properties: map[string]*genai.Schema{},
+	}
+
+	t := reflect.TypeOf(ReviewFinding{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("json")
+
+		typ := genai.TypeString
+		if field.Type.Kind() == reflect.Int {
+			typ = genai.TypeInteger
+		}
+
+		property := &genai.Schema{
+			Type:        typ,
+			Description: field.Tag.Get("desc"),
+		}
+		if name == "severity" {
+			property.Enum = []string{SeverityHigh, SeverityMedium, SeverityLow}
+		}
+
+		finding.Properties[name] = property
+		finding.Required = append(finding.Required, name)
+	}
+
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"findings": {
+				Type:  genai.TypeArray,
+				Items: finding,
+			},
+		},
+		Required: []string{"findings"},
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIF renders the review as a SARIF 2.1.0 log so editors and code scanning
+// tools can show the findings inline.
+func (r Review) SARIF() ([]byte, error) {
+	results := make([]sarifResult, 0, len(r.Findings))
+	for _, finding := range r.Findings {
+		level := "note"
+		switch finding.Severity {
+		case SeverityHigh:
+			level = "error"
+		case SeverityMedium:
+			level = "warning"
+		}
+
+		result := sarifResult{
+			RuleID:  "geminic/" + finding.Severity,
+			Level:   level,
+			Message: sarifMessage{Text: finding.Message},
+		}
+		if finding.File != "" {
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: finding.File},
+				},
+			}
+			if finding.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		results = append(results, result)
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "geminic",
+						InformationURI: "https://github.com/Beriholic/geminic",
+					},
+				},
+				Results: results,
+			},
+		},
+	}, "", "  ")
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReviewSARIF(t *testing.T) {
+	review := Review{Findings: []ReviewFinding{
+		{File: "internal/handler.go", Line: 42, Severity: SeverityHigh, Message: "err is ignored"},
+		{File: "README.md", Severity: SeverityMedium, Message: "the flag is not documented"},
+		{Severity: SeverityLow, Message: "the commit mixes two changes"},
+	}}
+
+	got, err := review.SARIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "review.sarif")
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, bytes.TrimSpace(want)) {
+		t.Errorf("SARIF() differs from %s:\n%s", golden, got)
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           *struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(got, &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != len(review.Findings) {
+		t.Fatalf("SARIF() has %d runs, want 1 with %d results", len(log.Runs), len(review.Findings))
+	}
+	for i, result := range log.Runs[0].Results {
+		finding := review.Findings[i]
+		if result.RuleID != "geminic/"+finding.Severity || result.Message.Text != finding.Message {
+			t.Errorf("results[%d] = %+v for %+v", i, result, finding)
+		}
+		if finding.File == "" {
+			if len(result.Locations) != 0 {
+				t.Errorf("results[%d] has locations without a file", i)
+			}
+			continue
+		}
+		location := result.Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URI != finding.File || (location.Region != nil) != (finding.Line > 0) {
+			t.Errorf("results[%d] location = %+v for %+v", i, location, finding)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package dto
+
+const (
+	SymbolAdded    = "added"
+	SymbolRemoved  = "removed"
+	SymbolModified = "modified"
+)
+
+// SymbolChange is a Go func, method, type, const or var that a commit adds,
+// removes or modifies.
+type SymbolChange struct {
+	File     string `json:"file"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Change   string `json:"change"`
+	Exported bool   `json:"exported"`
+}
+
+// RemovesAPI reports whether the change removes a symbol other packages may
+// depend on.
+func (s SymbolChange) RemovesAPI() bool {
+	return s.Change == SymbolRemoved && s.Exported
+}
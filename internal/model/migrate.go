@@ -0,0 +1,139 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/spf13/viper"
+)
+
+// CurrentVersion is the layout of the config file this version of geminic
+// reads and writes. A file without a version is version 1.
+const CurrentVersion = 2
+
+// migrations[i] upgrades the settings of a version i+1 file to version i+2.
+var migrations = []func(settings map[string]any){
+	func(settings map[string]any) {
+		migrateProfiles(settings)
+		migrateLocale(settings)
+	},
+}
+
+// migrateProfiles moves the flat key, model, model_provider and custom_url
+// into the default profile.
+func migrateProfiles(settings map[string]any) {
+	profile := make(map[string]any)
+	for _, setting := range Settings {
+		if value, ok := settings[setting.Key]; ok && setting.Profile {
+			profile[setting.Key] = value
+			delete(settings, setting.Key)
+		}
+	}
+	if len(profile) == 0 {
+		return
+	}
+
+	profiles, _ := settings["profiles"].(map[string]any)
+	if profiles == nil {
+		profiles = make(map[string]any)
+	}
+	profiles[DefaultProfile] = profile
+	settings["profiles"] = profiles
+}
+
+// migrateLocale turns the free text i18n of version 1, e.g. Chinese or zh,
+// into a locale. Values without one fall back to the default with a warning.
+func migrateLocale(settings map[string]any) {
+	value, ok := settings["i18n"].(string)
+	if !ok || value == "" {
+		return
+	}
+	locale, ok := i18n.Locale(value)
+	if !ok {
+		fmt.Fprintln(os.Stderr, i18n.T("config.bad_locale", value, i18n.DefaultLanguage))
+		locale = i18n.DefaultLanguage
+	}
+	settings["i18n"] = locale
+}
+
+// migrate upgrades the config file read into v to CurrentVersion, keeping a
+// copy of the old file next to it, and returns the upgraded settings.
+func migrate(v *viper.Viper) (*viper.Viper, error) {
+	version := 1
+	if v.IsSet("version") {
+		version = v.GetInt("version")
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config file version %d is newer than this geminic supports (%d), upgrade geminic", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return v, nil
+	}
+
+	path := v.ConfigFileUsed()
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := os.WriteFile(backup, content, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %v", err)
+	}
+
+	settings := v.AllSettings()
+	for ; version < CurrentVersion; version++ {
+		migrations[version-1](settings)
+	}
+	settings["version"] = CurrentVersion
+
+	migrated := viper.New()
+	migrated.SetConfigFile(path)
+	if err := migrated.MergeConfigMap(settings); err != nil {
+		return nil, fmt.Errorf("failed to migrate config file: %v", err)
+	}
+	if err := migrated.WriteConfigAs(path); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %v", err)
+	}
+
+	fmt.Fprintln(os.Stderr, i18n.T("config.migrated", path, CurrentVersion, backup))
+	return migrated, nil
+}
+
+// tableKeys are the lists of tables geminic reads as a whole.
+var tableKeys = []string{"fallbacks", "prices", "repos"}
+
+// unknownKeys returns the keys of settings geminic does not read, most
+// likely typos or leftovers, sorted.
+func unknownKeys(settings map[string]any, prefix string) []string {
+	var unknown []string
+	for key, value := range settings {
+		key = prefix + key
+		if knownKey(key) {
+			continue
+		}
+		if table, ok := value.(map[string]any); ok {
+			unknown = append(unknown, unknownKeys(table, key+".")...)
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func knownKey(key string) bool {
+	if key == "version" || slices.Contains(tableKeys, key) {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(key, "profiles."); ok {
+		if _, field, ok := strings.Cut(rest, "."); ok {
+			return slices.ContainsFunc(Settings, func(s Setting) bool { return s.Profile && s.Key == field })
+		}
+		return false
+	}
+	return slices.ContainsFunc(Settings, func(s Setting) bool { return !s.Profile && s.Key == key })
+}
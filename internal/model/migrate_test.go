@@ -0,0 +1,180 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func readConfig(t *testing.T, path string) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestMigrateProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     map[string]any
+	}{
+		{
+			name:     "flat provider settings",
+			settings: map[string]any{"key": "k", "model": "m", "emoji": true},
+			want: map[string]any{
+				"emoji":    true,
+				"profiles": map[string]any{DefaultProfile: map[string]any{"key": "k", "model": "m"}},
+			},
+		},
+		{
+			name:     "no provider settings",
+			settings: map[string]any{"emoji": true},
+			want:     map[string]any{"emoji": true},
+		},
+		{
+			name: "other profiles are kept",
+			settings: map[string]any{
+				"custom_url": "https://example.com",
+				"profiles":   map[string]any{"work": map[string]any{"model": "w"}},
+			},
+			want: map[string]any{
+				"profiles": map[string]any{
+					"work":         map[string]any{"model": "w"},
+					DefaultProfile: map[string]any{"custom_url": "https://example.com"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrateProfiles(tt.settings)
+			if !reflect.DeepEqual(tt.settings, tt.want) {
+				t.Errorf("migrateProfiles() = %#v\nwant %#v", tt.settings, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	const v1 = "key = \"secret\"\nmodel = \"gemini-2.5-flash\"\nemoji = true\n"
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(v1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := migrate(readConfig(t, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.GetInt("version") != CurrentVersion || v.GetString("profiles.default.key") != "secret" || v.IsSet("key") {
+		t.Errorf("migrated settings = %v", v.AllSettings())
+	}
+
+	backup, err := os.ReadFile(path + ".v1.bak")
+	if err != nil || string(backup) != v1 {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+	if info, err := os.Stat(path + ".v1.bak"); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("backup mode = %v, %v", info.Mode(), err)
+	}
+
+	written := readConfig(t, path)
+	if written.GetInt("version") != CurrentVersion || written.GetString("profiles.default.model") != "gemini-2.5-flash" || !written.GetBool("emoji") {
+		t.Errorf("written settings = %v", written.AllSettings())
+	}
+
+	// a current file is left alone
+	if _, err := migrate(written); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".v2.bak"); !os.IsNotExist(err) {
+		t.Errorf("current file was backed up again: %v", err)
+	}
+}
+
+func TestMigrateLocale(t *testing.T) {
+	tests := []struct {
+		i18n any
+		want any
+	}{
+		{"Chinese", "zh_CN"},
+		{"zh", "zh_CN"},
+		{"ja-jp", "ja_JP"},
+		{"Klingon", "en_US"},
+		{nil, nil},
+	}
+
+	for _, tt := range tests {
+		settings := map[string]any{}
+		if tt.i18n != nil {
+			settings["i18n"] = tt.i18n
+		}
+		migrateLocale(settings)
+		if settings["i18n"] != tt.want {
+			t.Errorf("migrateLocale(%v) = %v, want %v", tt.i18n, settings["i18n"], tt.want)
+		}
+	}
+}
+
+// TestLoadLegacyLocale loads a version 1 file written by geminic before i18n
+// took locales, which has to load, and load again once migrated.
+func TestLoadLegacyLocale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("key = \"k\"\nmodel = \"m\"\ni18n = \"Chinese\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	SetConfigPath(path)
+	t.Cleanup(func() { SetConfigPath("") })
+
+	for range 2 {
+		var c Config
+		if err := c.Load(); err != nil {
+			t.Fatal(err)
+		}
+		if c.I18n != "zh_CN" || c.Bilingual.Primary != "zh_CN" || c.Key != "k" {
+			t.Errorf("Load() = i18n %q, primary %q, key %q", c.I18n, c.Bilingual.Primary, c.Key)
+		}
+	}
+	if written := readConfig(t, path); written.GetString("i18n") != "zh_CN" {
+		t.Errorf("written i18n = %q", written.GetString("i18n"))
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("version = 99\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrate(readConfig(t, path)); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("migrate() error = %v", err)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	settings := map[string]any{
+		"version":  2,
+		"emoji":    true,
+		"emjoi":    true,
+		"key":      "flat keys belong to a profile",
+		"retry":    map[string]any{"max_attempts": 3, "max_attempt": 3},
+		"profiles": map[string]any{"work": map[string]any{"model": "m", "emoji": true}},
+		"repos":    []any{map[string]any{"path": "~/x", "anything": 1}},
+		"bilingual": map[string]any{
+			"secondary": "zh_CN",
+		},
+	}
+
+	want := []string{"emjoi", "key", "profiles.work.emoji", "retry.max_attempt"}
+	if got := unknownKeys(settings, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("unknownKeys() = %q, want %q", got, want)
+	}
+}
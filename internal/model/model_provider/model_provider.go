@@ -3,4 +3,9 @@ package model_provider
 const (
 	Gemini string = "Gemini"
 	OpenAI string = "OpenAI"
+	// Heuristic writes commit messages offline from the changed paths and
+	// symbols, without any model.
+	Heuristic string = "Heuristic"
 )
+
+var Providers = []string{Gemini, OpenAI, Heuristic}
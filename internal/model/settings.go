@@ -0,0 +1,213 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
+)
+
+type SettingKind string
+
+const (
+	KindString   SettingKind = "string"
+	KindBool     SettingKind = "bool"
+	KindInt      SettingKind = "int"
+	KindDuration SettingKind = "duration"
+	KindList     SettingKind = "list"
+)
+
+// Setting is a config key geminic config get, set and list work with. Lists
+// of tables such as fallbacks are only edited in the file.
+type Setting struct {
+	Key    string
+	Kind   SettingKind
+	Secret bool
+	// Profile settings are stored in the selected profile, under
+	// profiles.<name>.
+	Profile bool
+	// Get returns the value of the setting in c, defaults applied.
+	Get      func(c *Config) any
+	Validate func(value any) error
+}
+
+var Settings = []Setting{
+	{Key: "profile", Kind: KindString, Get: func(c *Config) any { return c.Profile }},
+	{Key: "key", Kind: KindString, Secret: true, Profile: true, Get: func(c *Config) any { return c.Key }},
+	{Key: "model", Kind: KindString, Profile: true, Get: func(c *Config) any { return c.Model }},
+	{Key: "model_provider", Kind: KindString, Profile: true, Get: func(c *Config) any { return c.ModelProvider }, Validate: validateProvider},
+	{Key: "custom_url", Kind: KindString, Profile: true, Get: func(c *Config) any { return c.CustomURL }, Validate: validateURL},
+	{Key: "emoji", Kind: KindBool, Get: func(c *Config) any { return c.Emoji }},
+	{Key: "i18n", Kind: KindString, Get: func(c *Config) any { return c.I18n }, Validate: validateLocale},
+	{Key: "language", Kind: KindString, Get: func(c *Config) any { return c.Language }, Validate: validateLanguage},
+	{Key: "review", Kind: KindBool, Get: func(c *Config) any { return c.Review }},
+	{Key: "protected_upstreams", Kind: KindList, Get: func(c *Config) any { return c.ProtectedUpstreams }},
+	{Key: "api_packages", Kind: KindList, Get: func(c *Config) any { return c.APIPackages }},
+	{Key: "commit_args", Kind: KindList, Get: func(c *Config) any { return c.CommitArgs }},
+	{Key: "bilingual.primary", Kind: KindString, Get: func(c *Config) any { return c.Bilingual.Primary }, Validate: validateLocale},
+	{Key: "bilingual.secondary", Kind: KindString, Get: func(c *Config) any { return c.Bilingual.Secondary }, Validate: validateLocale},
+	{Key: "bilingual.layout", Kind: KindString, Get: func(c *Config) any { return c.Bilingual.Layout }, Validate: oneOf(BilingualLayouts...)},
+	{Key: "retry.max_attempts", Kind: KindInt, Get: func(c *Config) any { return c.Retry.MaxAttempts }, Validate: positive},
+	{Key: "retry.initial_backoff", Kind: KindDuration, Get: func(c *Config) any { return c.Retry.InitialBackoff }, Validate: positive},
+	{Key: "retry.max_backoff", Kind: KindDuration, Get: func(c *Config) any { return c.Retry.MaxBackoff }, Validate: positive},
+	{Key: "retry.request_timeout", Kind: KindDuration, Get: func(c *Config) any { return c.Retry.RequestTimeout }, Validate: positive},
+	{Key: "retry.total_timeout", Kind: KindDuration, Get: func(c *Config) any { return c.Retry.TotalTimeout }, Validate: positive},
+	{Key: "cache.enabled", Kind: KindBool, Get: func(c *Config) any { return c.Cache.Enabled }},
+	{Key: "cache.ttl", Kind: KindDuration, Get: func(c *Config) any { return c.Cache.TTL }, Validate: positive},
+	{Key: "cache.max_size_mb", Kind: KindInt, Get: func(c *Config) any { return c.Cache.MaxSizeMB }, Validate: positive},
+}
+
+func LookupSetting(key string) (Setting, error) {
+	key = strings.ToLower(key)
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown config key %q, see geminic config list", key)
+}
+
+// Parse converts the arguments of geminic config set into a value of the
+// setting's kind. Lists take one argument per item or a comma separated one.
+func (s Setting) Parse(args []string) (any, error) {
+	if s.Kind == KindList {
+		var items []string
+		for _, arg := range args {
+			for _, item := range strings.Split(arg, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		return items, nil
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%s takes a single value", s.Key)
+	}
+	raw := args[0]
+
+	switch s.Kind {
+	case KindBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", s.Key)
+		}
+		return value, nil
+	case KindInt:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", s.Key)
+		}
+		return value, nil
+	case KindDuration:
+		value, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a duration such as 30s or 2m", s.Key)
+		}
+		return value, nil
+	}
+	return raw, nil
+}
+
+// Format renders value for display, masking secrets when mask is set.
+func (s Setting) Format(value any, mask bool) string {
+	switch value := value.(type) {
+	case []string:
+		return strings.Join(value, ",")
+	case string:
+		if mask && s.Secret {
+			return maskSecret(value)
+		}
+		return value
+	}
+	return fmt.Sprint(value)
+}
+
+// fileKey is where the setting is stored in the config file when profile is
+// selected.
+func (s Setting) fileKey(profile string) string {
+	if s.Profile {
+		return profileKey(profile, s.Key)
+	}
+	return s.Key
+}
+
+// fileValue is value as it is written to the config file.
+func (s Setting) fileValue(value any) any {
+	if duration, ok := value.(time.Duration); ok {
+		return duration.String()
+	}
+	return value
+}
+
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", len(secret)-8) + secret[len(secret)-4:]
+}
+
+func validateProvider(value any) error {
+	if provider := value.(string); provider != "" && !slices.Contains(model_provider.Providers, provider) {
+		return fmt.Errorf("unknown provider %q, use one of %s", provider, strings.Join(model_provider.Providers, ", "))
+	}
+	return nil
+}
+
+func validateURL(value any) error {
+	raw := value.(string)
+	if raw == "" {
+		return nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", raw)
+	}
+	return nil
+}
+
+func validateLocale(value any) error {
+	if locale := value.(string); locale != "" {
+		return i18n.Validate(locale)
+	}
+	return nil
+}
+
+func validateLanguage(value any) error {
+	if lang := value.(string); lang != "" && !slices.Contains(i18n.Languages(), i18n.Normalize(lang)) {
+		return fmt.Errorf("unsupported language %q, use one of %s", lang, strings.Join(i18n.Languages(), ", "))
+	}
+	return nil
+}
+
+func oneOf(values ...string) func(any) error {
+	return func(value any) error {
+		if !slices.Contains(values, value.(string)) {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+func positive(value any) error {
+	switch value := value.(type) {
+	case int:
+		if value <= 0 {
+			return fmt.Errorf("must be greater than 0")
+		}
+	case time.Duration:
+		if value <= 0 {
+			return fmt.Errorf("must be greater than 0")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLookupSetting(t *testing.T) {
+	if s, err := LookupSetting("Retry.Max_Attempts"); err != nil || s.Key != "retry.max_attempts" {
+		t.Errorf("LookupSetting() = %q, %v", s.Key, err)
+	}
+	if _, err := LookupSetting("retry.max_attempt"); err == nil {
+		t.Error("LookupSetting() of an unknown key succeeded")
+	}
+}
+
+func TestSettingParse(t *testing.T) {
+	tests := []struct {
+		key     string
+		args    []string
+		want    any
+		wantErr bool
+	}{
+		{"model", []string{"gemini-2.5-flash"}, "gemini-2.5-flash", false},
+		{"model", []string{"a", "b"}, nil, true},
+		{"model", nil, nil, true},
+		{"emoji", []string{"true"}, true, false},
+		{"emoji", []string{"0"}, false, false},
+		{"emoji", []string{"yes"}, nil, true},
+		{"retry.max_attempts", []string{"3"}, 3, false},
+		{"retry.max_attempts", []string{"three"}, nil, true},
+		{"cache.ttl", []string{"1h30m"}, 90 * time.Minute, false},
+		{"cache.ttl", []string{"90"}, nil, true},
+		{"commit_args", []string{"-S", "--no-verify"}, []string{"-S", "--no-verify"}, false},
+		{"commit_args", []string{"-S, --signoff,,"}, []string{"-S", "--signoff"}, false},
+		{"commit_args", nil, []string(nil), false},
+	}
+
+	for _, tt := range tests {
+		setting, err := LookupSetting(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := setting.Parse(tt.args)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s.Parse(%q) = %#v, %v, want %#v", tt.key, tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestSettingFormat(t *testing.T) {
+	key, _ := LookupSetting("key")
+	model, _ := LookupSetting("model")
+
+	tests := []struct {
+		setting Setting
+		value   any
+		mask    bool
+		want    string
+	}{
+		{key, "AIzaSyExampleKey1234", true, "AIza************1234"},
+		{key, "AIzaSyExampleKey1234", false, "AIzaSyExampleKey1234"},
+		{key, "short", true, "*****"},
+		{model, "gemini-2.5-flash", true, "gemini-2.5-flash"},
+		{model, []string{"-S", "--no-verify"}, false, "-S,--no-verify"},
+		{model, 2 * time.Minute, false, "2m0s"},
+		{model, true, false, "true"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.setting.Format(tt.value, tt.mask); got != tt.want {
+			t.Errorf("%s.Format(%v, %v) = %q, want %q", tt.setting.Key, tt.value, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestSettingValidate(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   any
+		wantErr bool
+	}{
+		{"model_provider", "", false},
+		{"model_provider", "OpenAI", false},
+		{"model_provider", "anthropic", true},
+		{"custom_url", "", false},
+		{"custom_url", "https://api.example.com/v1", false},
+		{"custom_url", "api.example.com", true},
+		{"custom_url", "ftp://example.com", true},
+		{"i18n", "zh_CN", false},
+		{"i18n", "xx_XX", true},
+		{"bilingual.layout", BilingualLayoutTrailer, false},
+		{"bilingual.layout", "line", true},
+		{"retry.max_attempts", 0, true},
+		{"retry.initial_backoff", time.Second, false},
+		{"retry.initial_backoff", -time.Second, true},
+	}
+
+	for _, tt := range tests {
+		setting, err := LookupSetting(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := setting.Validate(tt.value); (err != nil) != tt.wantErr {
+			t.Errorf("%s.Validate(%v) = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSettingFileKey(t *testing.T) {
+	key, _ := LookupSetting("key")
+	emoji, _ := LookupSetting("emoji")
+	ttl, _ := LookupSetting("cache.ttl")
+
+	if got := key.fileKey("work"); got != "profiles.work.key" {
+		t.Errorf("key.fileKey() = %q", got)
+	}
+	if got := emoji.fileKey("work"); got != "emoji" {
+		t.Errorf("emoji.fileKey() = %q", got)
+	}
+	if got := ttl.fileValue(time.Hour); got != "1h0m0s" {
+		t.Errorf("cache.ttl.fileValue() = %#v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+)
+
+// RetryCommit commits the staged changes with the message saved by the last
+// failed commit, without generating a new one. It reuses the options of the
+// failed commit, e.g. --amend, followed by args.
+func RetryCommit(args []string) error {
+	gitService := service.GetGitService()
+
+	if err := gitService.VerifyGitInstallation(); err != nil {
+		return err
+	}
+	if err := gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+
+	message, savedArgs, err := gitService.SavedMessage()
+	if err != nil {
+		return err
+	}
+	fmt.Println(ui.FormatText(i18n.T("commit.saved_message"), message))
+	if len(savedArgs) > 0 {
+		fmt.Println(i18n.T("commit.saved_args", strings.Join(savedArgs, " ")))
+	}
+
+	commitArgs := append(savedArgs, args...)
+	if err := checkCommitArgs(commitArgs); err != nil {
+		return err
+	}
+	if err := gitService.CommitChanges(message, commitArgs...); err != nil {
+		return fmt.Errorf("%v\nthe message is still saved, fix the problem and run geminic retry again", err)
+	}
+	fmt.Println(i18n.T("commit.committed"))
+
+	return gitService.RemoveSavedMessage()
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+	"github.com/Beriholic/geminic/internal/usage"
+)
+
+const (
+	ReviewFormatText  string = "text"
+	ReviewFormatJSON  string = "json"
+	ReviewFormatSARIF string = "sarif"
+)
+
+func ReviewChanges(ctx context.Context, format string) error {
+	if format != ReviewFormatText && format != ReviewFormatJSON && format != ReviewFormatSARIF {
+		return fmt.Errorf("unknown format %q, use text, json or sarif", format)
+	}
+
+	gitService := service.GetGitService()
+
+	if err := gitService.VerifyGitInstallation(); err != nil {
+		return err
+	}
+	if err := gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+
+	files, diff, err := gitService.DetectDiffChanges()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no staged changes found. stage your changes manually")
+	}
+
+	changes, err := gitService.StagedFileChanges()
+	if err != nil {
+		return err
+	}
+
+	llmService, err := service.NewLLMServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	commitDTO := &dto.CommitDTO{
+		Diff:    diff,
+		Files:   files,
+		Changes: changes,
+	}
+
+	if format == ReviewFormatText {
+		review, err := reviewStagedChanges(ctx, llmService, commitDTO)
+		if err != nil {
+			return err
+		}
+		fmt.Println(ui.FormatReview(review))
+		return nil
+	}
+
+	resp, err := llmService.Review(ctx, commitDTO)
+	if err != nil {
+		return err
+	}
+	recordUsage(resp, usage.KindReview, "")
+	out, err := encodeReview(resp.Review, format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// encodeReview renders review for tools, as JSON or as a SARIF log.
+func encodeReview(review *dto.Review, format string) ([]byte, error) {
+	if format == ReviewFormatSARIF {
+		return review.SARIF()
+	}
+	return json.MarshalIndent(review, "", "  ")
+}
+
+func reviewStagedChanges(
+	ctx context.Context,
+	llmService *service.LLMService,
+	commitDTO *dto.CommitDTO,
+) (*dto.Review, error) {
+	errChan := make(chan error, 1)
+	respChan := make(chan *llm.Response, 1)
+
+	err := ui.RenderSpinner(i18n.T("review.reviewing"), func() {
+		resp, err := llmService.Review(ctx, commitDTO)
+		errChan <- err
+		respChan <- resp
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := <-respChan, <-errChan
+	if err != nil {
+		return nil, err
+	}
+	recordUsage(resp, usage.KindReview, "")
+
+	return resp.Review, nil
+}
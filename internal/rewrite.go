@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+	"github.com/fatih/color"
+)
+
+func AmendCommit(ctx context.Context, userCommit string, args []string) error {
+	gitService := service.GetGitService()
+
+	if err := gitService.VerifyGitInstallation(); err != nil {
+		return err
+	}
+	if err := gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+	if !gitService.RefExists("HEAD") {
+		return fmt.Errorf("no commit to amend")
+	}
+	if upstream := gitService.ProtectedUpstream("HEAD", config.Get().ProtectedUpstreams); upstream != "" {
+		return fmt.Errorf("HEAD is already on protected upstream %s, refusing to amend it", upstream)
+	}
+
+	commitArgs, err := resolveCommitArgs(args)
+	if err != nil {
+		return err
+	}
+
+	files, diff, err := gitService.DetectAmendChanges()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("amend.detected", len(files)))
+	for idx, file := range files {
+		color.New(color.Bold).Printf("\t%d. %s\n", idx+1, file)
+	}
+
+	changes, err := gitService.AmendFileChanges()
+	if err != nil {
+		return err
+	}
+
+	llmService, err := service.NewLLMServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the amended commit replaces HEAD, so it is compared with its parent
+	base := ""
+	if gitService.RefExists("HEAD^") {
+		base = "HEAD^"
+	}
+	commitDTO := &dto.CommitDTO{
+		Commit:  userCommit,
+		Diff:    diff,
+		Files:   files,
+		Changes: changes,
+	}
+	describeChanges(commitDTO, base, "")
+
+	message, session, err := generateCommitMessage(ctx, llmService, commitDTO)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		fmt.Println(i18n.T("commit.cancelled"))
+		return nil
+	}
+
+	err = gitService.AmendChanges(message, commitArgs...)
+	session.committed(err)
+	if err != nil {
+		return saveFailedMessage(message, append([]string{"--amend"}, commitArgs...), err)
+	}
+	fmt.Println(i18n.T("amend.amended"))
+	return gitService.RemoveSavedMessage()
+}
+
+func RewordCommits(ctx context.Context, revRange string) (err error) {
+	gitService := service.GetGitService()
+
+	if err := gitService.VerifyGitInstallation(); err != nil {
+		return err
+	}
+	if err := gitService.VerifyGitRepository(); err != nil {
+		return err
+	}
+
+	if !strings.Contains(revRange, "..") && !strings.HasSuffix(revRange, "^!") {
+		revRange += "^!"
+	}
+
+	targets, err := gitService.ListCommits(revRange)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no commits found in %s", revRange)
+	}
+
+	protected := config.Get().ProtectedUpstreams
+	for _, commit := range targets {
+		if !gitService.IsAncestor(commit, "HEAD") {
+			return fmt.Errorf("commit %.7s is not on the current branch", commit)
+		}
+		if upstream := gitService.ProtectedUpstream(commit, protected); upstream != "" {
+			return fmt.Errorf("commit %.7s is already on protected upstream %s, refusing to reword it", commit, upstream)
+		}
+	}
+
+	// The rebase replays everything from the oldest target up to HEAD, so the
+	// todo list has to cover those commits too, not only the ones in range.
+	base := ""
+	replayRange := "HEAD"
+	if gitService.RefExists(targets[0] + "^") {
+		base, err = gitService.ResolveCommit(targets[0] + "^")
+		if err != nil {
+			return err
+		}
+		replayRange = base + "..HEAD"
+	}
+	commits, err := gitService.ListCommits(replayRange)
+	if err != nil {
+		return err
+	}
+	for _, commit := range commits {
+		if gitService.IsMergeCommit(commit) {
+			return fmt.Errorf("commit %.7s is a merge commit, rewording across merges is not supported", commit)
+		}
+	}
+
+	llmService, err := service.NewLLMServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	messages := make(map[string]string, len(targets))
+	// The messages are only committed by the rebase at the end, whatever
+	// happens before it leaves them uncommitted.
+	var sessions []*session
+	defer func() {
+		recordSessions(sessions, err)
+	}()
+	for idx, commit := range targets {
+		files, diff, err := gitService.DetectCommitChanges(commit)
+		if err != nil {
+			return err
+		}
+		changes, err := gitService.CommitFileChanges(commit)
+		if err != nil {
+			return err
+		}
+		original, err := gitService.CommitMessage(commit)
+		if err != nil {
+			return err
+		}
+
+		color.New(color.Bold).Printf("[%d/%d] %.7s\n", idx+1, len(targets), commit)
+		fmt.Println(ui.FormatText(i18n.T("reword.original"), original))
+
+		commitDTO := &dto.CommitDTO{
+			Diff:    diff,
+			Files:   files,
+			Changes: changes,
+		}
+		parent := ""
+		if gitService.RefExists(commit + "^") {
+			parent = commit + "^"
+		}
+		describeChanges(commitDTO, parent, commit)
+
+		message, session, err := generateCommitMessage(ctx, llmService, commitDTO)
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			fmt.Println(i18n.T("reword.kept"))
+			continue
+		}
+		messages[commit] = message
+		sessions = append(sessions, session)
+	}
+
+	if len(messages) == 0 {
+		fmt.Println(i18n.T("reword.nothing"))
+		return nil
+	}
+
+	if err := gitService.RewordCommits(base, commits, messages); err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("reword.done", len(messages)))
+	return nil
+}
@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRewordCommitsMerge(t *testing.T) {
+	initRepo(t)
+	commitFiles(t, "one", map[string]string{"a.txt": "a"})
+	runGit(t, "checkout", "-q", "-b", "side")
+	commitFiles(t, "two", map[string]string{"b.txt": "b"})
+	runGit(t, "checkout", "-q", "-")
+	commitFiles(t, "three", map[string]string{"c.txt": "c"})
+	runGit(t, "merge", "-q", "--no-edit", "side")
+	head := runGit(t, "rev-parse", "HEAD")
+
+	err := RewordCommits(context.Background(), "HEAD~2..HEAD")
+	if err == nil || !strings.Contains(err.Error(), "merge commit") {
+		t.Fatalf("RewordCommits across a merge = %v, want a merge commit error", err)
+	}
+	if got := runGit(t, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD = %s, want %s", got, head)
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+)
+
+func (g *GitService) StagedFileChanges() ([]dto.FileChange, error) {
+	return g.fileChanges("diff", "--cached")
+}
+
+func (g *GitService) AmendFileChanges() ([]dto.FileChange, error) {
+	base := "HEAD^"
+	if !g.RefExists(base) {
+		base = emptyTree
+	}
+	return g.fileChanges("diff", "--cached", base)
+}
+
+func (g *GitService) CommitFileChanges(rev string) ([]dto.FileChange, error) {
+	return g.fileChanges("show", "--format=", rev)
+}
+
+// fileChanges merges git's --raw and --numstat output for the same diff into
+// one record per file. args is the git command followed by its revisions;
+// the diff options are inserted after the command.
+func (g *GitService) fileChanges(args ...string) ([]dto.FileChange, error) {
+	run := func(format string) ([]string, error) {
+		cmdArgs := append([]string{args[0], "-M", "-C", "-z", format}, args[1:]...)
+		out, err := exec.Command("git", cmdArgs...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the changed files. %v", err)
+		}
+		return strings.Split(strings.TrimRight(string(out), "\x00"), "\x00"), nil
+	}
+
+	raw, err := run("--raw")
+	if err != nil {
+		return nil, err
+	}
+	numstat, err := run("--numstat")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := parseRaw(raw)
+	addNumstat(changes, numstat)
+	return changes, nil
+}
+
+// parseRaw parses the NUL separated fields of git diff --raw -z.
+func parseRaw(raw []string) []dto.FileChange {
+	var changes []dto.FileChange
+	for i := 0; i < len(raw); i++ {
+		// :oldmode newmode oldsha newsha status, then the path, or the old
+		// and new paths for renames and copies
+		fields := strings.Fields(strings.TrimPrefix(raw[i], ":"))
+		if len(fields) < 5 || i+1 >= len(raw) {
+			continue
+		}
+
+		change := dto.FileChange{Status: fields[4], OldMode: fields[0], NewMode: fields[1]}
+		i++
+		change.Path = raw[i]
+		if (change.Status[0] == 'R' || change.Status[0] == 'C') && i+1 < len(raw) {
+			i++
+			change.OldPath, change.Path = change.Path, raw[i]
+		}
+
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// addNumstat fills in the line counts of changes from the NUL separated
+// fields of git diff --numstat -z for the same diff.
+func addNumstat(changes []dto.FileChange, numstat []string) {
+	index := make(map[string]int)
+	for i, change := range changes {
+		index[change.Path] = i
+	}
+
+	for i := 0; i < len(numstat); i++ {
+		// additions<TAB>deletions<TAB>path, with an empty path followed by the
+		// old and new paths for renames and copies; binary files show "-"
+		fields := strings.SplitN(numstat[i], "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		path := fields[2]
+		if path == "" && i+2 < len(numstat) {
+			path = numstat[i+2]
+			i += 2
+		}
+
+		idx, ok := index[path]
+		if !ok {
+			continue
+		}
+		if fields[0] == "-" && fields[1] == "-" {
+			changes[idx].Binary = true
+			continue
+		}
+		changes[idx].Additions, _ = strconv.Atoi(fields[0])
+		changes[idx].Deletions, _ = strconv.Atoi(fields[1])
+	}
+}
+
+// FileAt returns the content of path at rev, or in the index when rev is "".
+func (g *GitService) FileAt(rev string, path string) ([]byte, error) {
+	out, err := exec.Command("git", "cat-file", "blob", rev+":"+path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s:%s. %v", rev, path, err)
+	}
+	return out, nil
+}
+
+// GoPackageFiles returns the non-test Go files directly in dir at rev, or in
+// the index when rev is "". dir is relative to the repository root.
+func (g *GitService) GoPackageFiles(rev string, dir string) (map[string][]byte, error) {
+	root, err := g.RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"ls-files", "--"}
+	if rev != "" {
+		args = []string{"ls-tree", "-r", "--name-only", rev, "--"}
+	}
+	if dir != "." {
+		args = append(args, dir+"/")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files of %s. %v", dir, err)
+	}
+
+	files := make(map[string][]byte)
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if path.Dir(file) != dir || !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := g.FileAt(rev, file)
+		if err != nil {
+			return nil, err
+		}
+		files[file] = src
+	}
+	return files, nil
+}
+
+// ObjectID returns the id of the object at path in rev, or in the index when
+// rev is "", e.g. the commit a submodule points to.
+func (g *GitService) ObjectID(rev string, path string) (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+":"+path).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s:%s. %v", rev, path, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
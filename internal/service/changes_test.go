@@ -0,0 +1,131 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+)
+
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v %s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestParseRawAndNumstat(t *testing.T) {
+	const a, b = "1111111", "2222222"
+	tests := []struct {
+		name    string
+		raw     []string
+		numstat []string
+		want    []dto.FileChange
+	}{
+		{"empty diff", []string{""}, []string{""}, nil},
+		{
+			name:    "modified",
+			raw:     []string{":100644 100644 " + a + " " + b + " M", "a.go"},
+			numstat: []string{"3\t1\ta.go"},
+			want:    []dto.FileChange{{Status: "M", Path: "a.go", OldMode: "100644", NewMode: "100644", Additions: 3, Deletions: 1}},
+		},
+		{
+			name: "added and deleted",
+			raw: []string{
+				":000000 100755 0000000 " + b + " A", "run.sh",
+				":100644 000000 " + a + " 0000000 D", "old.txt",
+			},
+			numstat: []string{"2\t0\trun.sh", "0\t5\told.txt"},
+			want: []dto.FileChange{
+				{Status: "A", Path: "run.sh", OldMode: "000000", NewMode: "100755", Additions: 2},
+				{Status: "D", Path: "old.txt", OldMode: "100644", NewMode: "000000", Deletions: 5},
+			},
+		},
+		{
+			name:    "rename",
+			raw:     []string{":100644 100644 " + a + " " + b + " R090", "old name.go", "new name.go"},
+			numstat: []string{"1\t1\t", "old name.go", "new name.go"},
+			want:    []dto.FileChange{{Status: "R090", Path: "new name.go", OldPath: "old name.go", OldMode: "100644", NewMode: "100644", Additions: 1, Deletions: 1}},
+		},
+		{
+			name:    "binary",
+			raw:     []string{":100644 100644 " + a + " " + b + " M", "logo.png"},
+			numstat: []string{"-\t-\tlogo.png"},
+			want:    []dto.FileChange{{Status: "M", Path: "logo.png", OldMode: "100644", NewMode: "100644", Binary: true}},
+		},
+		{
+			name:    "numstat for an unknown path is ignored",
+			raw:     []string{":100644 100644 " + a + " " + b + " M", "a.go"},
+			numstat: []string{"9\t9\tb.go"},
+			want:    []dto.FileChange{{Status: "M", Path: "a.go", OldMode: "100644", NewMode: "100644"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRaw(tt.raw)
+			addNumstat(got, tt.numstat)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStagedFileChanges(t *testing.T) {
+	initRepo(t)
+	g := &GitService{}
+
+	files := map[string]string{
+		"keep.txt":  "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n",
+		"gone.txt":  "bye\n",
+		"script.sh": "echo hi\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-q", "-m", "init")
+
+	runGit(t, "mv", "keep.txt", "moved.txt")
+	if err := os.WriteFile("moved.txt", []byte(files["keep.txt"]+"nine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("data.bin", []byte{0, 1, 2, 0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, "rm", "-q", "gone.txt")
+	runGit(t, "add", ".")
+	runGit(t, "update-index", "--chmod=+x", "script.sh")
+
+	changes, err := g.StagedFileChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byPath := make(map[string]dto.FileChange)
+	for _, change := range changes {
+		byPath[change.Path] = change
+	}
+	if len(byPath) != 4 {
+		t.Fatalf("StagedFileChanges() = %+v, want 4 files", changes)
+	}
+	if c := byPath["moved.txt"]; c.Status[0] != 'R' || c.OldPath != "keep.txt" || c.Additions != 1 || c.Deletions != 0 {
+		t.Errorf("rename = %+v", c)
+	}
+	if c := byPath["gone.txt"]; c.Status != "D" || c.Deletions != 1 {
+		t.Errorf("delete = %+v", c)
+	}
+	if c := byPath["data.bin"]; c.Status != "A" || !c.Binary {
+		t.Errorf("binary = %+v", c)
+	}
+	if c := byPath["script.sh"]; !c.ModeChanged() || c.NewMode != "100755" {
+		t.Errorf("mode change = %+v", c)
+	}
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 )
@@ -38,6 +42,49 @@ func (g *GitService) VerifyGitRepository() error {
 	return nil
 }
 
+func (g *GitService) RepoRoot() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("current directory is not a git repository. %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GitVersion returns the version git reports, e.g. 2.43.0.
+func (g *GitService) GitVersion() (string, error) {
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("git is not installed. %v", err)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "git version "), nil
+}
+
+// OperationInProgress returns the operation the repository is in the middle
+// of: rebase, merge, cherry-pick or revert, or "" when there is none.
+func (g *GitService) OperationInProgress() (string, error) {
+	operations := []struct{ file, name string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	}
+	for _, operation := range operations {
+		path, err := g.gitPath(operation.file)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return operation.name, nil
+		}
+	}
+	return "", nil
+}
+
+func (g *GitService) IsDetachedHead() bool {
+	return exec.Command("git", "symbolic-ref", "-q", "HEAD").Run() != nil
+}
+
 func (g *GitService) DetectDiffChanges() ([]string, string, error) {
 	files, err := exec.Command("git", "diff", "--cached", "--diff-algorithm=minimal", "--name-only").
 		Output()
@@ -47,7 +94,7 @@ func (g *GitService) DetectDiffChanges() ([]string, string, error) {
 	filesStr := strings.TrimSpace(string(files))
 
 	if filesStr == "" {
-		return nil, "", fmt.Errorf("no changes detected")
+		return nil, "", nil
 	}
 
 	diff, err := exec.Command("git", "diff", "--cached", "--diff-algorithm=minimal").Output()
@@ -58,11 +105,399 @@ func (g *GitService) DetectDiffChanges() ([]string, string, error) {
 	return strings.Split(filesStr, "\n"), string(diff), nil
 }
 
-func (g *GitService) CommitChanges(message string) error {
-	_, err := exec.Command("git", "commit", "-m", message).Output()
+// CommitChanges commits with message, passing args on to git commit as they
+// are.
+func (g *GitService) CommitChanges(message string, args ...string) error {
+	if err := g.commitWithMessage(message, args...); err != nil {
+		return fmt.Errorf("failed to commit changes. %v", err)
+	}
+
+	return nil
+}
+
+// commitWithMessage runs git commit -F with message written to a temp file,
+// so multi-line messages reach git exactly as they were written.
+func (g *GitService) commitWithMessage(message string, args ...string) error {
+	msgFile, err := os.CreateTemp("", "geminic-msg-*.txt")
 	if err != nil {
+		return err
+	}
+	defer os.Remove(msgFile.Name())
+
+	if _, err := msgFile.WriteString(message); err != nil {
+		msgFile.Close()
+		return err
+	}
+	if err := msgFile.Close(); err != nil {
+		return err
+	}
+
+	args = append([]string{"commit"}, args...)
+	args = append(args, "-F", msgFile.Name())
+	return runCommit(args...)
+}
+
+// CommitWithoutMessage runs git commit with args only, for options such as
+// --fixup that make git write the message itself.
+func (g *GitService) CommitWithoutMessage(args ...string) error {
+	if err := runCommit(append([]string{"commit"}, args...)...); err != nil {
 		return fmt.Errorf("failed to commit changes. %v", err)
 	}
 
 	return nil
 }
+
+// runCommit runs git attached to the terminal, so hook output is shown as it
+// happens and gpg can ask for a passphrase. stderr is also captured and
+// returned with the error, so a failing hook is reported with its output.
+func runCommit(args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	if err := cmd.Run(); err != nil {
+		if out := strings.TrimSpace(stderr.String()); out != "" {
+			return fmt.Errorf("%v\n%s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// savedMessageFile is where the message of a failed commit is kept for retry,
+// and savedArgsFile the git commit options it was run with, one per line.
+const (
+	savedMessageFile = "GEMINIC_MSG"
+	savedArgsFile    = "GEMINIC_MSG_ARGS"
+)
+
+// gitPath resolves name inside the git directory, the way
+// git rev-parse --git-path does.
+func (g *GitService) gitPath(name string) (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--git-path", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the git directory. %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SaveMessage writes message to .git/GEMINIC_MSG, and the git commit options
+// it was committed with next to it, and returns the path of the message.
+func (g *GitService) SaveMessage(message string, args []string) (string, error) {
+	path, err := g.gitPath(savedMessageFile)
+	if err != nil {
+		return "", err
+	}
+	argsPath, err := g.gitPath(savedArgsFile)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(message), 0o600); err != nil {
+		return "", fmt.Errorf("failed to save the commit message. %v", err)
+	}
+	if err := os.WriteFile(argsPath, []byte(strings.Join(args, "\n")), 0o600); err != nil {
+		return "", fmt.Errorf("failed to save the commit options. %v", err)
+	}
+	return path, nil
+}
+
+// SavedMessage returns the saved message and the commit options saved with
+// it.
+func (g *GitService) SavedMessage() (message string, args []string, err error) {
+	path, err := g.gitPath(savedMessageFile)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("no saved commit message found")
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the saved commit message. %v", err)
+	}
+
+	argsPath, err := g.gitPath(savedArgsFile)
+	if err != nil {
+		return "", nil, err
+	}
+	argsData, err := os.ReadFile(argsPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the saved commit options. %v", err)
+	}
+	if len(argsData) > 0 {
+		args = strings.Split(string(argsData), "\n")
+	}
+	return string(data), args, nil
+}
+
+func (g *GitService) RemoveSavedMessage() error {
+	for _, name := range []string{savedMessageFile, savedArgsFile} {
+		path, err := g.gitPath(name)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove the saved commit message. %v", err)
+		}
+	}
+	return nil
+}
+
+// Editor returns the editor git itself would use: $GIT_EDITOR, core.editor,
+// $VISUAL, $EDITOR and finally vi.
+func (g *GitService) Editor() (string, error) {
+	out, err := exec.Command("git", "var", "GIT_EDITOR").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find an editor. %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// commentCharCandidates are the characters core.commentChar=auto picks from,
+// in git's order.
+const commentCharCandidates = "#;@!$%^&|:"
+
+// CommentChar returns core.commentChar, resolving "auto" to the first
+// candidate no line of message starts with.
+func (g *GitService) CommentChar(message string) string {
+	out, _ := exec.Command("git", "config", "--get", "core.commentChar").Output()
+	commentChar := strings.TrimSpace(string(out))
+
+	switch commentChar {
+	case "":
+		return "#"
+	case "auto":
+		used := make(map[rune]bool)
+		for _, line := range strings.Split(message, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				used[[]rune(line)[0]] = true
+			}
+		}
+		for _, candidate := range commentCharCandidates {
+			if !used[candidate] {
+				return string(candidate)
+			}
+		}
+		return "#"
+	}
+	return commentChar
+}
+
+func (g *GitService) StagedSummary() (string, error) {
+	out, err := exec.Command("git", "diff", "--cached", "--stat").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// emptyTree is the well-known hash of git's empty tree, used as the diff base
+// for root commits.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// DetectAmendChanges returns what HEAD with the staged changes changes
+// compared with its parent. When that is nothing, e.g. HEAD is an empty
+// commit, the diff is HEAD's message and stat so there is still something to
+// describe.
+func (g *GitService) DetectAmendChanges() ([]string, string, error) {
+	base := "HEAD^"
+	if !g.RefExists(base) {
+		base = emptyTree
+	}
+
+	files, err := exec.Command("git", "diff", "--cached", "--diff-algorithm=minimal", "--name-only", base).
+		Output()
+	if err != nil {
+		return nil, "", err
+	}
+	filesStr := strings.TrimSpace(string(files))
+
+	if filesStr == "" {
+		show, err := exec.Command("git", "show", "--stat", "--format=%B", "HEAD").Output()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read HEAD. %v", err)
+		}
+		return nil, string(show), nil
+	}
+
+	diff, err := exec.Command("git", "diff", "--cached", "--diff-algorithm=minimal", base).Output()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return strings.Split(filesStr, "\n"), string(diff), nil
+}
+
+func (g *GitService) DetectCommitChanges(rev string) ([]string, string, error) {
+	files, err := exec.Command("git", "show", "--format=", "--diff-algorithm=minimal", "--name-only", rev).
+		Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read commit %s. %v", rev, err)
+	}
+	filesStr := strings.TrimSpace(string(files))
+
+	diff, err := exec.Command("git", "show", "--format=", "--diff-algorithm=minimal", rev).Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read commit %s. %v", rev, err)
+	}
+
+	if filesStr == "" {
+		return nil, string(diff), nil
+	}
+	return strings.Split(filesStr, "\n"), string(diff), nil
+}
+
+func (g *GitService) AmendChanges(message string, args ...string) error {
+	if err := g.commitWithMessage(message, append([]string{"--amend"}, args...)...); err != nil {
+		return fmt.Errorf("failed to amend commit. %v", err)
+	}
+
+	return nil
+}
+
+func (g *GitService) RefExists(ref string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() == nil
+}
+
+func (g *GitService) IsAncestor(rev string, ref string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", rev, ref).Run() == nil
+}
+
+func (g *GitService) ResolveCommit(rev string) (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--verify", rev+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s. %v", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (g *GitService) ListCommits(revRange string) ([]string, error) {
+	out, err := exec.Command("git", "rev-list", "--reverse", revRange).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits in %s. %v", revRange, err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+func (g *GitService) IsMergeCommit(rev string) bool {
+	return g.RefExists(rev + "^2")
+}
+
+func (g *GitService) CommitMessage(rev string) (string, error) {
+	out, err := exec.Command("git", "log", "-1", "--format=%B", rev).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read message of %s. %v", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ProtectedUpstream returns the first of upstreams that already contains rev,
+// or "" when rev has not been published to any of them.
+func (g *GitService) ProtectedUpstream(rev string, upstreams []string) string {
+	for _, upstream := range upstreams {
+		if g.RefExists(upstream) && g.IsAncestor(rev, upstream) {
+			return upstream
+		}
+	}
+	return ""
+}
+
+// RewordCommits rewrites the messages of the given commits by replaying
+// base..HEAD through a non-interactive rebase. commits must list every commit
+// in base..HEAD oldest first; commits without an entry in messages are kept
+// as they are. An empty base rebases from the root commit.
+func (g *GitService) RewordCommits(base string, commits []string, messages map[string]string) error {
+	dir, err := os.MkdirTemp("", "geminic-reword-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir. %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var todo strings.Builder
+	for idx, commit := range commits {
+		fmt.Fprintf(&todo, "pick %s\n", commit)
+
+		message, ok := messages[commit]
+		if !ok {
+			continue
+		}
+		msgFile := filepath.Join(dir, fmt.Sprintf("%d.msg", idx))
+		if err := os.WriteFile(msgFile, []byte(message), 0o600); err != nil {
+			return fmt.Errorf("failed to write commit message. %v", err)
+		}
+		fmt.Fprintf(&todo, "exec git commit --amend --allow-empty --quiet -F %s\n", shellQuote(msgFile))
+	}
+
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(todo.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write rebase todo. %v", err)
+	}
+
+	args := []string{"rebase", "--interactive", "--autostash"}
+	if base == "" {
+		args = append(args, "--root")
+	} else {
+		args = append(args, base)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoFile))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = exec.Command("git", "rebase", "--abort").Run()
+		return fmt.Errorf("failed to reword commits. %v\n%s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// maxHistoryBytes caps the history sent to the model, which would otherwise
+// grow with every commit that touched a busy file.
+const maxHistoryBytes = 256 << 10
+
+// LoadHistory returns what explain has to describe: git show for a single
+// revision, git log -p for a range, or the log of a path following renames.
+// The path does not have to exist anymore, only to have history.
+func (g *GitService) LoadHistory(target string, maxCount int) (string, error) {
+	var args []string
+	switch {
+	case strings.Contains(target, ".."):
+		args = []string{"log", "--stat", "-p", fmt.Sprintf("--max-count=%d", maxCount), target}
+	case g.RefExists(target):
+		args = []string{"show", "--stat", "-p", target}
+	default:
+		out, err := exec.Command("git", "log", "--format=%H", "--max-count=1", "--", target).Output()
+		if err != nil || strings.TrimSpace(string(out)) == "" {
+			return "", fmt.Errorf("%s is neither a revision nor a path with history", target)
+		}
+		args = []string{"log", "--follow", "--stat", "-p", fmt.Sprintf("--max-count=%d", maxCount), "--", target}
+	}
+
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to load history of %s. %v", target, err)
+	}
+
+	history := strings.TrimSpace(string(out))
+	if history == "" {
+		return "", fmt.Errorf("no history found for %s", target)
+	}
+	return truncateHistory(history, maxHistoryBytes), nil
+}
+
+// truncateHistory cuts history to at most limit bytes, at a line boundary,
+// and says so at the end.
+func truncateHistory(history string, limit int) string {
+	if len(history) <= limit {
+		return history
+	}
+	cut := strings.LastIndexByte(history[:limit], '\n')
+	if cut < 0 {
+		cut = limit
+	}
+	return history[:cut] + "\n[history truncated]"
+}
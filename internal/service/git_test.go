@@ -0,0 +1,167 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// initRepo creates an empty git repository and makes it the working
+// directory for the rest of the test.
+func initRepo(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v %s", err, out)
+	}
+	t.Chdir(dir)
+	// rebases run git commit themselves, without runGit's identity
+	runGit(t, "config", "user.name", "test")
+	runGit(t, "config", "user.email", "test@example.com")
+	runGit(t, "config", "commit.gpgsign", "false")
+}
+
+func TestSavedMessage(t *testing.T) {
+	initRepo(t)
+	g := &GitService{}
+
+	if _, _, err := g.SavedMessage(); err == nil {
+		t.Fatal("SavedMessage without a saved message succeeded")
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no options", nil},
+		{"amend", []string{"--amend", "--no-verify"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.SaveMessage("feat: add x\n\nbody", tt.args); err != nil {
+				t.Fatal(err)
+			}
+			message, args, err := g.SavedMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message != "feat: add x\n\nbody" || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("SavedMessage() = %q, %q", message, args)
+			}
+
+			if err := g.RemoveSavedMessage(); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := g.SavedMessage(); err == nil {
+				t.Error("saved message still there after RemoveSavedMessage")
+			}
+		})
+	}
+}
+
+// commit commits a change to name with message and returns its hash.
+func commit(t *testing.T, name string, message string) string {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(message), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, "add", name)
+	runGit(t, "commit", "-q", "-m", message)
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// messages returns the subjects of every commit on HEAD, oldest first.
+func messages(t *testing.T) []string {
+	t.Helper()
+	out, err := exec.Command("git", "log", "--reverse", "--format=%s").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
+
+func TestRewordCommits(t *testing.T) {
+	tests := []struct {
+		name   string
+		target int
+		want   []string
+	}{
+		{"middle commit", 1, []string{"one", "reworded", "three"}},
+		{"root commit", 0, []string{"reworded", "two", "three"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initRepo(t)
+			commits := []string{
+				commit(t, "a.txt", "one"),
+				commit(t, "b.txt", "two"),
+				commit(t, "c.txt", "three"),
+			}
+			g := &GitService{}
+
+			base := ""
+			if tt.target > 0 {
+				base = commits[tt.target-1]
+			}
+			err := g.RewordCommits(base, commits[tt.target:], map[string]string{commits[tt.target]: "reworded"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := messages(t); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewordCommitsFailure(t *testing.T) {
+	initRepo(t)
+	first := commit(t, "a.txt", "one")
+	second := commit(t, "b.txt", "two")
+	head := commit(t, "c.txt", "three")
+
+	hook := filepath.Join(".git", "hooks", "commit-msg")
+	if err := os.MkdirAll(filepath.Dir(hook), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &GitService{}
+	err := g.RewordCommits(first, []string{second, head}, map[string]string{second: "reworded"})
+	if err == nil {
+		t.Fatal("RewordCommits with a failing commit-msg hook succeeded")
+	}
+
+	if operation, err := g.OperationInProgress(); err != nil || operation != "" {
+		t.Errorf("OperationInProgress() = %q, %v after a failed reword", operation, err)
+	}
+	if got, err := g.ResolveCommit("HEAD"); err != nil || got != head {
+		t.Errorf("HEAD = %s, %v, want %s", got, err, head)
+	}
+	if got := messages(t); !reflect.DeepEqual(got, []string{"one", "two", "three"}) {
+		t.Errorf("messages = %q after a failed reword", got)
+	}
+}
+
+func TestDetectAmendChangesEmptyCommit(t *testing.T) {
+	initRepo(t)
+	runGit(t, "commit", "-q", "--allow-empty", "-m", "chore: start")
+	g := &GitService{}
+
+	files, diff, err := g.DetectAmendChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 || !strings.Contains(diff, "chore: start") {
+		t.Errorf("DetectAmendChanges() = %q, %q", files, diff)
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"net"
 
 	"github.com/Beriholic/geminic/internal/config"
 	"github.com/Beriholic/geminic/internal/llm"
@@ -10,26 +12,104 @@ import (
 )
 
 type LLMService struct {
-	LLM llm.LLM
-}
-
-func init() {
+	LLM       llm.LLM
+	Fallbacks []llm.LLM
 }
 
 func NewLLMServer(ctx context.Context) (*LLMService, error) {
 	cfg := config.Get()
-	llm, err := llm.GetLLM(ctx, cfg)
+	primary, err := llm.GetLLM(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	fallbacks, err := llm.GetFallbackLLMs(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 	return &LLMService{
-		LLM: llm,
+		LLM:       primary,
+		Fallbacks: fallbacks,
 	}, nil
 }
 
-func (l *LLMService) Generate(ctx context.Context, dto *dto.CommitDTO) (*dto.GitCommit, error) {
+// generate sends req to the primary LLM and then to each fallback in turn,
+// for as long as they fail with an error another provider might not have.
+// When all of them could not be reached, the offline generator writes the
+// commit message instead. Any other error, e.g. a rejected key, is returned
+// so the user can fix it.
+func (l *LLMService) generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	resp, err := l.LLM.Generate(ctx, req)
+	allUnreachable := err != nil && unreachable(err)
+	for _, fallback := range l.Fallbacks {
+		if err == nil || !shouldFallback(err) {
+			break
+		}
+		resp, err = fallback.Generate(ctx, req)
+		allUnreachable = allUnreachable && unreachable(err)
+	}
+
+	if err != nil && allUnreachable && req.Kind == llm.KindCommit && ctx.Err() == nil {
+		offline, offlineErr := llm.NewHeuristicLLM().Generate(ctx, req)
+		if offlineErr != nil {
+			return nil, err
+		}
+		offline.FallbackReason = err
+		return offline, nil
+	}
+	return resp, err
+}
+
+func shouldFallback(err error) bool {
+	return llm.Retryable(err) || errors.Is(err, llm.ErrModelNotFound) || unreachable(err)
+}
+
+// unreachable reports whether err means the provider could not be reached
+// or did not answer, rather than that it rejected the request.
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return llm.Retryable(err) || errors.As(err, &opErr) || errors.As(err, &dnsErr)
+}
+
+type GenerateOptions struct {
+	// History continues the conversation with earlier candidates and the
+	// user's feedback on them.
+	History []llm.Message
+	NoCache bool
+	OnChunk func(llm.Chunk)
+}
+
+func (l *LLMService) Generate(
+	ctx context.Context,
+	dto *dto.CommitDTO,
+	opts GenerateOptions,
+) (*llm.Response, error) {
 	prompt := prompt.NewPrompt().Build(dto)
-	return l.LLM.Generate(ctx, prompt)
+	return l.generate(ctx, &llm.Request{
+		Kind:    llm.KindCommit,
+		Prompt:  prompt,
+		History: opts.History,
+		OnChunk: opts.OnChunk,
+		NoCache: opts.NoCache,
+		Commit:  dto,
+	})
+}
+
+func (l *LLMService) Explain(ctx context.Context, history string, onChunk func(llm.Chunk)) (*llm.Response, error) {
+	prompt := prompt.NewPrompt().BuildExplain(history)
+	return l.generate(ctx, &llm.Request{
+		Kind:    llm.KindText,
+		Prompt:  prompt,
+		OnChunk: onChunk,
+	})
+}
+
+func (l *LLMService) Review(ctx context.Context, dto *dto.CommitDTO) (*llm.Response, error) {
+	prompt := prompt.NewPrompt().BuildReview(dto)
+	return l.generate(ctx, &llm.Request{
+		Kind:   llm.KindReview,
+		Prompt: prompt,
+	})
 }
 
 func (l *LLMService) ModelList(ctx context.Context) ([]string, error) {
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/model/model_provider"
+)
+
+// fakeLLM answers as provider, or fails with err, and records the call and
+// the last request.
+type fakeLLM struct {
+	provider string
+	err      error
+	calls    *[]string
+	req      *llm.Request
+}
+
+func (f *fakeLLM) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	*f.calls = append(*f.calls, f.provider)
+	f.req = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &llm.Response{Provider: f.provider}, nil
+}
+
+func (f *fakeLLM) ModelList(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func TestGenerateOffline(t *testing.T) {
+	unavailable := &llm.Error{Kind: llm.ErrUnavailable, Err: errors.New("503")}
+	authFailed := &llm.Error{Kind: llm.ErrAuthFailed, Err: errors.New("401")}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		primary  error
+		fallback error
+		want     string
+		wantErr  error
+	}{
+		{"unavailable", unavailable, unavailable, model_provider.Heuristic, nil},
+		{"connection refused", refused, unavailable, model_provider.Heuristic, nil},
+		{"auth failed", authFailed, nil, "", llm.ErrAuthFailed},
+		{"fallback auth failed", unavailable, authFailed, "", llm.ErrAuthFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			l := &LLMService{
+				LLM:       &fakeLLM{provider: "primary", err: tt.primary, calls: &calls},
+				Fallbacks: []llm.LLM{&fakeLLM{provider: "fallback", err: tt.fallback, calls: &calls}},
+			}
+			resp, err := l.generate(context.Background(), &llm.Request{
+				Kind:   llm.KindCommit,
+				Commit: &dto.CommitDTO{Files: []string{"README.md"}},
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || llm.Hint(err) == "" {
+					t.Fatalf("generate() error = %v, want %v with a hint", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Provider != tt.want || resp.FallbackReason == nil {
+				t.Errorf("generate() = %s, %v, want %s", resp.Provider, resp.FallbackReason, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFallbacks(t *testing.T) {
+	unavailable := &llm.Error{Kind: llm.ErrUnavailable, Err: errors.New("503")}
+	modelNotFound := &llm.Error{Kind: llm.ErrModelNotFound, Err: errors.New("404")}
+	blocked := &llm.Error{Kind: llm.ErrBlockedBySafety, Err: errors.New("blocked")}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls []string
+		want      string
+		wantErr   error
+	}{
+		{"primary answers", []error{nil, nil, nil}, []string{"0"}, "0", nil},
+		{"in order", []error{unavailable, modelNotFound, nil}, []string{"0", "1", "2"}, "2", nil},
+		{"stops on other errors", []error{unavailable, blocked, nil}, []string{"0", "1"}, "", llm.ErrBlockedBySafety},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			var llms []llm.LLM
+			for i, err := range tt.errs {
+				llms = append(llms, &fakeLLM{provider: fmt.Sprint(i), err: err, calls: &calls})
+			}
+			l := &LLMService{LLM: llms[0], Fallbacks: llms[1:]}
+
+			resp, err := l.generate(context.Background(), &llm.Request{Kind: llm.KindText})
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("called %q, want %q", calls, tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("generate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Provider != tt.want {
+				t.Errorf("generate() answered by %s, want %s", resp.Provider, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainRequest(t *testing.T) {
+	var calls []string
+	fake := &fakeLLM{provider: "primary", calls: &calls}
+	l := &LLMService{LLM: fake}
+
+	history := "commit 1234567\n\n    feat: add review\n\ndiff --git a/review.go b/review.go"
+	if _, err := l.Explain(context.Background(), history, func(llm.Chunk) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := fake.req
+	if req.Kind != llm.KindText || req.OnChunk == nil || req.Commit != nil || len(req.History) != 0 {
+		t.Errorf("Explain() sent kind %v, commit %v, history %v", req.Kind, req.Commit, req.History)
+	}
+	for _, want := range []string{
+		"explain to a new team member",
+		"<GitHistory>",
+		history,
+		"in en_US language",
+	} {
+		if !strings.Contains(req.Prompt, want) {
+			t.Errorf("explain prompt does not contain %q:\n%s", want, req.Prompt)
+		}
+	}
+	if strings.Contains(req.Prompt, "GitCommitType") {
+		t.Errorf("explain prompt asks for a commit message:\n%s", req.Prompt)
+	}
+}
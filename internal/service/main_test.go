@@ -0,0 +1,25 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain points geminic's config, data and cache directories at a
+// temporary home, so the prompts are built from the default config rather
+// than the user's.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "geminic-home")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	os.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
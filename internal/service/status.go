@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FileStatus is one entry of git status --porcelain=v2. Staged and Unstaged
+// are the X and Y status letters, '.' meaning unchanged.
+type FileStatus struct {
+	Path      string
+	OrigPath  string
+	Staged    byte
+	Unstaged  byte
+	Untracked bool
+	Unmerged  bool
+}
+
+// PartiallyStaged reports whether the file has staged changes and further
+// changes in the work tree that would be left out of the commit.
+func (f FileStatus) PartiallyStaged() bool {
+	return !f.Untracked && f.Staged != '.' && f.Unstaged != '.'
+}
+
+func (f FileStatus) HasUnstaged() bool {
+	return f.Untracked || f.Unstaged != '.'
+}
+
+func (g *GitService) Status() ([]FileStatus, error) {
+	out, err := exec.Command("git", "status", "--porcelain=v2", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git status. %v", err)
+	}
+
+	return parseStatus(string(out)), nil
+}
+
+// parseStatus parses the output of git status --porcelain=v2 -z.
+func parseStatus(out string) []FileStatus {
+	var files []FileStatus
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
+			continue
+		}
+
+		switch entry[0] {
+		case '1':
+			// 1 XY sub mH mI mW hH hI path
+			fields := strings.SplitN(entry, " ", 9)
+			if len(fields) < 9 {
+				continue
+			}
+			files = append(files, FileStatus{Path: fields[8], Staged: fields[1][0], Unstaged: fields[1][1]})
+		case '2':
+			// 2 XY sub mH mI mW hH hI Xscore path, followed by the original path
+			fields := strings.SplitN(entry, " ", 10)
+			if len(fields) < 10 || i+1 >= len(entries) {
+				continue
+			}
+			i++
+			files = append(files, FileStatus{Path: fields[9], OrigPath: entries[i], Staged: fields[1][0], Unstaged: fields[1][1]})
+		case 'u':
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			fields := strings.SplitN(entry, " ", 11)
+			if len(fields) < 11 {
+				continue
+			}
+			files = append(files, FileStatus{Path: fields[10], Staged: fields[1][0], Unstaged: fields[1][1], Unmerged: true})
+		case '?':
+			files = append(files, FileStatus{Path: entry[2:], Staged: '.', Unstaged: '?', Untracked: true})
+		}
+	}
+
+	return files
+}
+
+// StageTracked stages modifications and deletions of tracked files, like
+// git commit -a.
+func (g *GitService) StageTracked() error {
+	if out, err := exec.Command("git", "add", "--update").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage tracked files. %v\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// StageFiles stages paths as reported by Status, which are relative to the
+// repository root.
+func (g *GitService) StageFiles(paths ...string) error {
+	root, err := g.RepoRoot()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", append([]string{"add", "--all", "--"}, paths...)...)
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage files. %v\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		name string
+		out  string
+		want []FileStatus
+	}{
+		{"clean", "", nil},
+		{
+			name: "modified",
+			out:  "1 M. N... 100644 100644 100644 " + hash + " " + hash + " a.go\x00",
+			want: []FileStatus{{Path: "a.go", Staged: 'M', Unstaged: '.'}},
+		},
+		{
+			name: "partly staged with a space in the path",
+			out:  "1 MM N... 100644 100644 100644 " + hash + " " + hash + " dir/my file.go\x00",
+			want: []FileStatus{{Path: "dir/my file.go", Staged: 'M', Unstaged: 'M'}},
+		},
+		{
+			name: "rename",
+			out:  "2 R. N... 100644 100644 100644 " + hash + " " + hash + " R100 new.go\x00old.go\x00",
+			want: []FileStatus{{Path: "new.go", OrigPath: "old.go", Staged: 'R', Unstaged: '.'}},
+		},
+		{
+			name: "unmerged",
+			out:  "u UU N... 100644 100644 100644 100644 " + hash + " " + hash + " " + hash + " c.go\x00",
+			want: []FileStatus{{Path: "c.go", Staged: 'U', Unstaged: 'U', Unmerged: true}},
+		},
+		{
+			name: "untracked and ignored",
+			out:  "? new file.txt\x00! build/out\x00",
+			want: []FileStatus{{Path: "new file.txt", Staged: '.', Unstaged: '?', Untracked: true}},
+		},
+		{
+			name: "truncated entries are skipped",
+			out:  "1 M. N... 100644\x002 R. N... 100644 100644 100644 " + hash + " " + hash + " R100 new.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStatus(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStatus() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileStatus(t *testing.T) {
+	tests := []struct {
+		file     FileStatus
+		partly   bool
+		unstaged bool
+	}{
+		{FileStatus{Staged: 'M', Unstaged: '.'}, false, false},
+		{FileStatus{Staged: 'M', Unstaged: 'M'}, true, true},
+		{FileStatus{Staged: '.', Unstaged: 'D'}, false, true},
+		{FileStatus{Staged: '.', Unstaged: '?', Untracked: true}, false, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.file.PartiallyStaged(); got != tt.partly {
+			t.Errorf("%+v PartiallyStaged() = %v", tt.file, got)
+		}
+		if got := tt.file.HasUnstaged(); got != tt.unstaged {
+			t.Errorf("%+v HasUnstaged() = %v", tt.file, got)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	initRepo(t)
+	g := &GitService{}
+
+	for _, file := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(file, []byte(file+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out, err := exec.Command("git", "add", "a.txt").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v %s", err, out)
+	}
+
+	files, err := g.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FileStatus{
+		{Path: "a.txt", Staged: 'A', Unstaged: '.'},
+		{Path: "b.txt", Staged: '.', Unstaged: '?', Untracked: true},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Status() = %+v\nwant %+v", files, want)
+	}
+}
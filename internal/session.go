@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/Beriholic/geminic/internal/history"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/llm/prompt"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/usage"
+)
+
+type candidate struct {
+	// history is the conversation after the prompt that produced resp.
+	history []llm.Message
+	resp    *llm.Response
+	message string
+	edited  string
+	outcome string
+}
+
+// session holds the candidates generated for one commit so the user can step
+// back to earlier ones. Each candidate is written to the usage log and the
+// history exactly once, when the session is flushed.
+type session struct {
+	repo       string
+	diffHash   string
+	candidates []*candidate
+	current    int
+}
+
+func newSession(commitDTO *dto.CommitDTO) *session {
+	repo, _ := service.GetGitService().RepoRoot()
+	return &session{
+		repo:     repo,
+		diffHash: history.DiffHash(commitDTO.Diff),
+		current:  -1,
+	}
+}
+
+func (s *session) add(resp *llm.Response, history []llm.Message) *candidate {
+	c := &candidate{history: history, resp: resp, message: resp.Commit.String()}
+	s.candidates = append(s.candidates, c)
+	s.current = len(s.candidates) - 1
+	return c
+}
+
+// refine returns the conversation asking the model to rework the current
+// candidate according to the user's feedback.
+func (s *session) refine(feedback string) []llm.Message {
+	current := s.candidates[s.current]
+
+	history := make([]llm.Message, 0, len(current.history)+2)
+	history = append(history, current.history...)
+	return append(history,
+		llm.Message{Role: llm.RoleModel, Content: current.resp.Text},
+		llm.Message{Role: llm.RoleUser, Content: prompt.Refine(feedback)},
+	)
+}
+
+// committed records the session once the message it produced was committed,
+// or rejected by git with err.
+func (s *session) committed(err error) {
+	if err != nil && s.current >= 0 {
+		s.candidates[s.current].outcome = usage.OutcomeFailed
+	}
+	s.flush()
+}
+
+// recordSessions records sessions whose messages were committed together, or
+// all rejected with err.
+func recordSessions(sessions []*session, err error) {
+	for _, s := range sessions {
+		s.committed(err)
+	}
+}
+
+func (s *session) finish(outcome string, edited string) {
+	if s.current < 0 {
+		return
+	}
+	s.candidates[s.current].outcome = outcome
+	s.candidates[s.current].edited = edited
+}
+
+// flush records every candidate. Candidates without an outcome were rolled
+// away from, except the one on screen when the session ended without a
+// decision, e.g. on an error.
+func (s *session) flush() {
+	now := time.Now()
+	entries := make([]history.Entry, 0, len(s.candidates))
+
+	for idx, c := range s.candidates {
+		outcome := c.outcome
+		if outcome == "" {
+			outcome = usage.OutcomeRolled
+			if idx == s.current {
+				outcome = usage.OutcomeCancelled
+			}
+		}
+
+		_ = usage.Append(usage.Record{
+			Time:             now,
+			Repo:             s.repo,
+			Kind:             usage.KindCommit,
+			Provider:         c.resp.Provider,
+			Model:            c.resp.Model,
+			Cached:           c.resp.Cached,
+			PromptTokens:     c.resp.Usage.PromptTokens,
+			CompletionTokens: c.resp.Usage.CompletionTokens,
+			Outcome:          outcome,
+		})
+
+		entries = append(entries, history.Entry{
+			Time:     now,
+			Repo:     s.repo,
+			DiffHash: s.diffHash,
+			Provider: c.resp.Provider,
+			Model:    c.resp.Model,
+			Message:  c.message,
+			Edited:   c.edited,
+			Outcome:  outcome,
+		})
+	}
+
+	_ = history.Append(entries...)
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/llm/prompt"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+)
+
+// requestLLM records the last request and answers with resp.
+type requestLLM struct {
+	req  *llm.Request
+	resp *llm.Response
+}
+
+func (r *requestLLM) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	r.req = req
+	return r.resp, nil
+}
+
+func (r *requestLLM) ModelList(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func TestSessionRefine(t *testing.T) {
+	first := &llm.Response{
+		Text:   `{"typ":"feat","msg":"add the review command with sarif output"}`,
+		Commit: &dto.GitCommit{Typ: "feat", Msg: "add the review command with sarif output"},
+	}
+	second := &llm.Response{
+		Text:   `{"typ":"feat","msg":"add review"}`,
+		Commit: &dto.GitCommit{Typ: "feat", Msg: "add review"},
+	}
+	s := &session{current: -1}
+
+	s.add(first, nil)
+	history := s.refine("shorter")
+	want := []llm.Message{
+		{Role: llm.RoleModel, Content: first.Text},
+		{Role: llm.RoleUser, Content: prompt.Refine("shorter")},
+	}
+	if !reflect.DeepEqual(history, want) {
+		t.Fatalf("refine() = %+v\nwant %+v", history, want)
+	}
+
+	// the next round continues the conversation the second candidate came from
+	s.add(second, history)
+	followUp := s.refine("scope should be api")
+	want = append(want,
+		llm.Message{Role: llm.RoleModel, Content: second.Text},
+		llm.Message{Role: llm.RoleUser, Content: prompt.Refine("scope should be api")},
+	)
+	if !reflect.DeepEqual(followUp, want) {
+		t.Fatalf("refine() = %+v\nwant %+v", followUp, want)
+	}
+	if len(history) != 2 {
+		t.Errorf("refine() changed the history of the previous candidate to %+v", history)
+	}
+
+	fake := &requestLLM{resp: second}
+	llmService := &service.LLMService{LLM: fake}
+	commitDTO := &dto.CommitDTO{Diff: "diff --git a/review.go b/review.go", Files: []string{"review.go"}}
+	if _, err := llmService.Generate(context.Background(), commitDTO, service.GenerateOptions{History: followUp}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fake.req.History, followUp) || !strings.Contains(fake.req.Prompt, "review.go") {
+		t.Errorf("Generate() sent history %+v with prompt:\n%s", fake.req.History, fake.req.Prompt)
+	}
+}
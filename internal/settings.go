@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/ui"
+	"github.com/fatih/color"
+)
+
+func GetSetting(key string) error {
+	setting, err := model.LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	fmt.Println(setting.Format(setting.Get(config.Get()), false))
+	return nil
+}
+
+func SetSetting(key string, args []string) error {
+	if err := model.SetValue(key, args); err != nil {
+		return err
+	}
+	path, err := model.ConfigPath()
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("config.saved", path))
+	return nil
+}
+
+func ListSettings() error {
+	cfg := config.Get()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, setting := range model.Settings {
+		fmt.Fprintf(w, "%s\t%s\n", setting.Key, setting.Format(setting.Get(cfg), true))
+	}
+	return w.Flush()
+}
+
+// EditSettings opens the config file in the editor git would use and
+// validates it once the editor exits.
+func EditSettings() error {
+	editor, err := service.GetGitService().Editor()
+	if err != nil {
+		return err
+	}
+
+	path, err := model.ConfigPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create config directory: %v", err)
+		}
+		if err := os.WriteFile(path, nil, 0600); err != nil {
+			return fmt.Errorf("failed to create config file: %v", err)
+		}
+	}
+	if err := ui.OpenInEditor(editor, path); err != nil {
+		return err
+	}
+	return ValidateSettings()
+}
+
+func ValidateSettings() error {
+	path, err := model.ConfigPath()
+	if err != nil {
+		return err
+	}
+	var cfg model.Config
+	if err := cfg.Load(); err != nil {
+		return err
+	}
+	color.New(color.FgGreen).Println(i18n.T("config.valid", path))
+	return nil
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/llm"
+	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/usage"
+)
+
+// recordUsage appends resp to the usage log. Failing to write the log must
+// never get in the way of committing, so errors are dropped.
+func recordUsage(resp *llm.Response, kind string, outcome string) {
+	repo, _ := service.GetGitService().RepoRoot()
+	_ = usage.Append(usage.Record{
+		Time:             time.Now(),
+		Repo:             repo,
+		Kind:             kind,
+		Provider:         resp.Provider,
+		Model:            resp.Model,
+		Cached:           resp.Cached,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		Outcome:          outcome,
+	})
+}
+
+func ShowStats(days int) error {
+	since := time.Time{}
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	records, err := usage.Load(since)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Println(i18n.T("stats.empty"))
+		return nil
+	}
+
+	prices := config.Get().Prices
+
+	printSummaries(i18n.T("stats.model"), usage.Summarize(records, prices, func(r usage.Record) string {
+		return fmt.Sprintf("%s/%s", r.Provider, r.Model)
+	}))
+	printSummaries(i18n.T("stats.repo"), shortenRepos(usage.Summarize(records, prices, func(r usage.Record) string {
+		if r.Repo == "" {
+			return "-"
+		}
+		return r.Repo
+	})))
+	printSummaries(i18n.T("stats.day"), usage.Summarize(records, prices, func(r usage.Record) string {
+		return r.Time.Local().Format(time.DateOnly)
+	}))
+
+	return nil
+}
+
+// shortenRepos shows repositories summarized by root path by their directory
+// name, unless another repository has the same one.
+func shortenRepos(summaries []*usage.Summary) []*usage.Summary {
+	names := make(map[string]int)
+	for _, s := range summaries {
+		names[filepath.Base(s.Key)]++
+	}
+	for _, s := range summaries {
+		if name := filepath.Base(s.Key); names[name] == 1 {
+			s.Key = name
+		}
+	}
+	return summaries
+}
+
+func printSummaries(title string, summaries []*usage.Summary) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, i18n.T("stats.header", title))
+
+	for _, s := range summaries {
+		cost := fmt.Sprintf("%.4f", s.Cost)
+		if s.UnpricedRequests > 0 {
+			cost += i18n.T("stats.unpriced", s.UnpricedRequests)
+		}
+
+		acceptance := "-"
+		if rate := s.AcceptanceRate(); rate >= 0 {
+			acceptance = fmt.Sprintf("%.0f%%", rate*100)
+		}
+
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t%s\t%d\t%d\t%d\n",
+			s.Key,
+			s.Requests,
+			s.Cached,
+			s.PromptTokens,
+			s.CompletionTokens,
+			cost,
+			acceptance,
+			s.Rolled,
+			s.Cancelled,
+			s.Failed,
+		)
+	}
+
+	w.Flush()
+	fmt.Println()
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/usage"
+)
+
+func TestShortenRepos(t *testing.T) {
+	summaries := shortenRepos([]*usage.Summary{
+		{Key: "/home/me/work/api"},
+		{Key: "/home/me/oss/api"},
+		{Key: "/home/me/geminic"},
+		{Key: "-"},
+	})
+
+	want := []string{"/home/me/work/api", "/home/me/oss/api", "geminic", "-"}
+	for i, s := range summaries {
+		if s.Key != want[i] {
+			t.Errorf("summaries[%d].Key = %q, want %q", i, s.Key, want[i])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/config"
+	"github.com/Beriholic/geminic/internal/gosym"
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+)
+
+// symbolChanges compares the declarations of every changed Go file between
+// base and rev, see describeChanges. Files that do not parse, e.g. half
+// written code, are left out rather than failing the commit.
+func symbolChanges(base, rev string, changes []dto.FileChange) []dto.SymbolChange {
+	gitService := service.GetGitService()
+
+	var symbols []dto.SymbolChange
+	for _, change := range changes {
+		if !strings.HasSuffix(change.Path, ".go") || change.Binary {
+			continue
+		}
+
+		var before, after map[string]gosym.Symbol
+		if oldPath := basePath(change); base != "" && oldPath != "" {
+			src, err := gitService.FileAt(base, oldPath)
+			if err != nil {
+				continue
+			}
+			if before, err = gosym.Parse(oldPath, src); err != nil {
+				continue
+			}
+		}
+		if change.Status != "D" {
+			src, err := gitService.FileAt(rev, change.Path)
+			if err != nil {
+				continue
+			}
+			if after, err = gosym.Parse(change.Path, src); err != nil {
+				continue
+			}
+		}
+
+		symbols = append(symbols, gosym.Diff(change.Path, before, after)...)
+	}
+
+	return symbols
+}
+
+// basePath returns the path change had before it, or "" for a new file.
+func basePath(change dto.FileChange) string {
+	switch change.Status[0] {
+	case 'A':
+		return ""
+	case 'R', 'C':
+		return change.OldPath
+	}
+	return change.Path
+}
+
+// breakingChanges compares the exported API of every Go package touched by
+// changes between base and rev, see describeChanges. Main packages cannot be
+// imported, so their changes never break anyone. With api_packages set only
+// the packages it matches are checked, e.g. to leave out internal/.
+func breakingChanges(base, rev string, changes []dto.FileChange) []string {
+	gitService := service.GetGitService()
+	if base == "" {
+		return nil
+	}
+
+	patterns := config.Get().APIPackages
+
+	var dirs []string
+	for _, change := range changes {
+		for _, file := range []string{change.Path, change.OldPath} {
+			if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			if dir := path.Dir(file); !slices.Contains(dirs, dir) && isAPIPackage(dir, patterns) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	slices.Sort(dirs)
+
+	var breaking []string
+	for _, dir := range dirs {
+		before, err := gitService.GoPackageFiles(base, dir)
+		if err != nil || len(before) == 0 {
+			continue
+		}
+		after, err := gitService.GoPackageFiles(rev, dir)
+		if err != nil {
+			continue
+		}
+
+		pkgName, oldAPI, err := gosym.API(before)
+		if err != nil || pkgName == "main" {
+			continue
+		}
+		_, newAPI, err := gosym.API(after)
+		if err != nil {
+			continue
+		}
+
+		for _, change := range gosym.Breaking(oldAPI, newAPI) {
+			breaking = append(breaking, fmt.Sprintf("%s: %s", dir, change))
+		}
+	}
+
+	return breaking
+}
+
+// isAPIPackage reports whether dir matches one of patterns, each either a
+// directory or dir/... for it and everything below. No patterns match every
+// directory.
+func isAPIPackage(dir string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(pattern, "./")
+		if pattern == "..." {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if dir == prefix || strings.HasPrefix(dir, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if dir == strings.TrimSuffix(pattern, "/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Beriholic/geminic/internal/model/dto"
+	"github.com/Beriholic/geminic/internal/service"
+)
+
+func TestIsAPIPackage(t *testing.T) {
+	tests := []struct {
+		dir      string
+		patterns []string
+		want     bool
+	}{
+		{"internal/llm", nil, true},
+		{".", []string{"."}, true},
+		{"cmd", []string{"."}, false},
+		{"pkg", []string{"pkg/..."}, true},
+		{"pkg/api/v2", []string{"./pkg/..."}, true},
+		{"pkgs", []string{"pkg/..."}, false},
+		{"internal/llm", []string{".", "pkg/..."}, false},
+		{"internal/llm", []string{"./..."}, true},
+		{"api", []string{"api/"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := isAPIPackage(tt.dir, tt.patterns); got != tt.want {
+			t.Errorf("isAPIPackage(%q, %q) = %v, want %v", tt.dir, tt.patterns, got, tt.want)
+		}
+	}
+}
+
+// TestDescribeChanges checks the data amend and reword get: a commit compared
+// with its parent, and the index compared with the parent of HEAD.
+func TestDescribeChanges(t *testing.T) {
+	initRepo(t)
+	gitService := service.GetGitService()
+
+	commitFiles(t, "init", map[string]string{
+		"api/api.go": "package api\n\nfunc A() {}\n\nfunc B() {}\n",
+		"go.mod":     "module example.com/x\n\ngo 1.24\n\nrequire github.com/spf13/cobra v1.8.0\n",
+	})
+	commitFiles(t, "drop B", map[string]string{
+		"api/api.go": "package api\n\nfunc A() {}\n",
+		"go.mod":     "module example.com/x\n\ngo 1.24\n\nrequire github.com/spf13/cobra v1.8.1\n",
+	})
+
+	changes, err := gitService.CommitFileChanges("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reworded := &dto.CommitDTO{Changes: changes}
+	describeChanges(reworded, "HEAD^", "HEAD")
+
+	if len(reworded.Symbols) != 1 || !reworded.Symbols[0].RemovesAPI() || reworded.Symbols[0].Name != "B" {
+		t.Errorf("Symbols = %+v", reworded.Symbols)
+	}
+	if want := []string{"api: removed func B"}; !reflect.DeepEqual(reworded.Breaking, want) {
+		t.Errorf("Breaking = %q, want %q", reworded.Breaking, want)
+	}
+	if want := []string{"bump github.com/spf13/cobra from v1.8.0 to v1.8.1"}; !reflect.DeepEqual(reworded.Bumps, want) {
+		t.Errorf("Bumps = %q, want %q", reworded.Bumps, want)
+	}
+
+	// amending HEAD with nothing staged still sees what HEAD changed
+	changes, err = gitService.AmendFileChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	amended := &dto.CommitDTO{Changes: changes}
+	describeChanges(amended, "HEAD^", "")
+	if !reflect.DeepEqual(amended.Breaking, reworded.Breaking) {
+		t.Errorf("amend Breaking = %q, want %q", amended.Breaking, reworded.Breaking)
+	}
+	if !missingBreakingFooter(amended, "refactor(api): drop B") {
+		t.Error("message without a BREAKING CHANGE footer accepted")
+	}
+	if missingBreakingFooter(amended, "refactor(api)!: drop B\n\nBREAKING CHANGE: B is gone") {
+		t.Error("message with a BREAKING CHANGE footer rejected")
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/Beriholic/geminic/internal/i18n"
+)
+
+// RenderExternalEditor opens editor on a temp file holding message followed
+// by summary as comment lines, the way git commit does, and returns what the
+// user saved with the comment lines stripped. An empty result means abort.
+func RenderExternalEditor(editor string, message string, commentChar string, summary string) (string, error) {
+	msgFile, err := os.CreateTemp("", "COMMIT_EDITMSG-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(msgFile.Name())
+
+	var content strings.Builder
+	content.WriteString(strings.TrimRight(message, "\n"))
+	content.WriteString("\n\n")
+	for _, line := range strings.Split(i18n.T("editor.help", commentChar), "\n") {
+		fmt.Fprintf(&content, "%s %s\n", commentChar, line)
+	}
+	if summary != "" {
+		fmt.Fprintf(&content, "%s\n%s %s\n", commentChar, commentChar, i18n.T("editor.changes"))
+		for _, line := range strings.Split(summary, "\n") {
+			fmt.Fprintf(&content, "%s %s\n", commentChar, line)
+		}
+	}
+
+	if _, err := msgFile.WriteString(content.String()); err != nil {
+		msgFile.Close()
+		return "", fmt.Errorf("failed to write temp file: %v", err)
+	}
+	if err := msgFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %v", err)
+	}
+
+	if err := runEditor(editor, msgFile.Name()); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(msgFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited message: %v", err)
+	}
+
+	return stripCommitMessage(string(edited), commentChar), nil
+}
+
+// OpenInEditor lets the user edit the file at path in editor.
+func OpenInEditor(editor string, path string) error {
+	return runEditor(editor, path)
+}
+
+// runEditor runs editor the way git does, through the shell so that values
+// like "code --wait" work.
+func runEditor(editor string, path string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(editor)
+		if len(fields) == 0 {
+			return fmt.Errorf("no editor configured")
+		}
+		cmd = exec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %v", editor, err)
+	}
+	return nil
+}
+
+// stripCommitMessage mirrors git's --cleanup=strip: comment lines and
+// trailing whitespace go, runs of blank lines collapse into one and leading
+// and trailing blank lines are dropped.
+func stripCommitMessage(message string, commentChar string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, commentChar) {
+			continue
+		}
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,29 @@
+package ui
+
+import "testing"
+
+func TestStripCommitMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		commentChar string
+		want        string
+	}{
+		{"unchanged", "feat: add x\n\nbody", "#", "feat: add x\n\nbody"},
+		{"comments", "# Please enter the message\nfeat: add x\n# staged:\n#\tmodified: a.go\n", "#", "feat: add x"},
+		{"other comment char", "; comment\n#1 is not a comment\n", ";", "#1 is not a comment"},
+		{"trailing whitespace", "feat: add x  \t\r\n\nbody \n", "#", "feat: add x\n\nbody"},
+		{"blank runs", "\n\n\nfeat: add x\n\n\n\nbody\n\n\n", "#", "feat: add x\n\nbody"},
+		{"blank line left by a comment", "feat: add x\n\n# comment\n\nbody", "#", "feat: add x\n\nbody"},
+		{"only comments", "# nothing\n#\n", "#", ""},
+		{"indented comment is kept", "feat: add x\n\n  # not a comment", "#", "feat: add x\n\n  # not a comment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripCommitMessage(tt.message, tt.commentChar); got != tt.want {
+				t.Errorf("stripCommitMessage(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Beriholic/geminic/internal/service"
+	"github.com/Beriholic/geminic/internal/i18n"
+	"github.com/Beriholic/geminic/internal/model/dto"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
+	"github.com/fatih/color"
 )
 
 type action string
@@ -16,22 +18,81 @@ var base *huh.Theme = huh.ThemeBase()
 const (
 	CONFIRM     action = "CONFIRM"
 	REGENERATE  action = "REGENERATE"
+	REFINE      action = "REFINE"
+	PREVIOUS    action = "PREVIOUS"
+	NEXT        action = "NEXT"
 	EDIT_COMMIT action = "EDIT_COMMIT"
+	EDIT_EDITOR action = "EDIT_EDITOR"
 	CANCEL      action = "CANCEL"
 )
 
-func RenderActionForm() (action, error) {
+func RenderActionForm(hasPrevious bool, hasNext bool) (action, error) {
+	var curAction action
+
+	options := []huh.Option[action]{
+		huh.NewOption(i18n.T("action.yes"), CONFIRM),
+		huh.NewOption(i18n.T("action.roll"), REGENERATE),
+		huh.NewOption(i18n.T("action.refine"), REFINE),
+	}
+	if hasPrevious {
+		options = append(options, huh.NewOption(i18n.T("action.previous"), PREVIOUS))
+	}
+	if hasNext {
+		options = append(options, huh.NewOption(i18n.T("action.next"), NEXT))
+	}
+	options = append(options,
+		huh.NewOption(i18n.T("action.edit"), EDIT_COMMIT),
+		huh.NewOption(i18n.T("action.open_editor"), EDIT_EDITOR),
+		huh.NewOption(i18n.T("action.no"), CANCEL),
+	)
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[action]().
+				Title(i18n.T("form.confirm")).
+				Options(options...).
+				Value(&curAction).
+				WithTheme(base),
+		))
+
+	if err := form.Run(); err != nil {
+		return CANCEL, err
+	}
+
+	return curAction, nil
+}
+
+func RenderFeedbackForm() (string, error) {
+	var feedback string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(i18n.T("form.feedback")).
+				Placeholder(i18n.T("form.feedback_placeholder")).
+				Value(&feedback),
+		),
+	).WithTheme(base)
+
+	if err := form.Run(); err != nil {
+		return "", err
+	}
+
+	return feedback, nil
+}
+
+func RenderReuseForm() (action, error) {
 	var curAction action
 
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[action]().
-				Title("Is that what you want?").
+				Title(i18n.T("form.reuse")).
 				Options(
-					huh.NewOption("Yes", CONFIRM),
-					huh.NewOption("Roll", REGENERATE),
-					huh.NewOption("Edit", EDIT_COMMIT),
-					huh.NewOption("No", CANCEL),
+					huh.NewOption(i18n.T("action.yes"), CONFIRM),
+					huh.NewOption(i18n.T("action.edit"), EDIT_COMMIT),
+					huh.NewOption(i18n.T("action.open_editor"), EDIT_EDITOR),
+					huh.NewOption(i18n.T("action.no"), CANCEL),
 				).
 				Value(&curAction).
 				WithTheme(base),
@@ -44,35 +105,35 @@ func RenderActionForm() (action, error) {
 	return curAction, nil
 }
 
-func RenderEditorForm(commit string) (action, error) {
+func RenderEditorForm(commit string) (string, action, error) {
 	var confirmEdit bool = false
 
 	input := huh.NewForm(
 		huh.NewGroup(
-			huh.NewText().Title("Edit commit message").CharLimit(200).Value(&commit),
+			huh.NewText().Title(i18n.T("form.edit")).CharLimit(200).Value(&commit),
 		),
 	)
 
 	confirm := huh.NewConfirm().
-		Title("Confirm edit?").
-		Affirmative("Yes").
-		Negative("No").
+		Title(i18n.T("form.edit_confirm")).
+		Affirmative(i18n.T("action.yes")).
+		Negative(i18n.T("action.no")).
 		Value(&confirmEdit).
 		WithTheme(base)
 
 	if err := input.Run(); err != nil {
-		return CANCEL, err
+		return "", CANCEL, err
 	}
 
 	if err := confirm.Run(); err != nil {
-		return CANCEL, err
+		return "", CANCEL, err
 	}
 
 	if !confirmEdit {
-		return CANCEL, nil
+		return "", CANCEL, nil
 	}
 
-	return CONFIRM, service.GetGitService().CommitChanges(commit)
+	return commit, CONFIRM, nil
 }
 
 func RenderSpinner(title string, action func()) error {
@@ -95,6 +156,36 @@ func FormatText(title string, context string) string {
 	return formattedText
 }
 
+func FormatReview(review *dto.Review) string {
+	if len(review.Findings) == 0 {
+		return FormatText(i18n.T("review.title"), i18n.T("review.no_findings"))
+	}
+
+	formattedText := fmt.Sprintf("┃ %s\n", i18n.T("review.findings", len(review.Findings)))
+	for _, finding := range review.Findings {
+		severity := color.New(color.Bold, color.FgHiBlack)
+		switch finding.Severity {
+		case dto.SeverityHigh:
+			severity = color.New(color.Bold, color.FgRed)
+		case dto.SeverityMedium:
+			severity = color.New(color.Bold, color.FgYellow)
+		}
+
+		location := finding.File
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+		}
+
+		formattedText += fmt.Sprintf("┃ %s %s\n┃   %s\n",
+			severity.Sprintf("%-6s", finding.Severity),
+			location,
+			strings.TrimSpace(finding.Message),
+		)
+	}
+
+	return formattedText
+}
+
 func RenderStringsSelect(models []string) (string, error) {
 	if len(models) == 0 {
 		return "", nil
@@ -108,7 +199,7 @@ func RenderStringsSelect(models []string) (string, error) {
 	}
 
 	selectField := huh.NewSelect[string]().
-		Title("Selecting a Gemini Model.").
+		Title(i18n.T("form.select_model")).
 		Options(huhOptions...).
 		Value(&selectedModel)
 
@@ -121,3 +212,59 @@ func RenderStringsSelect(models []string) (string, error) {
 	}
 	return selectedModel, nil
 }
+
+// RenderIndexSelect lets the user pick one of labels and returns its index,
+// or -1 when there is nothing to pick.
+func RenderIndexSelect(title string, labels []string) (int, error) {
+	if len(labels) == 0 {
+		return -1, nil
+	}
+
+	selected := -1
+
+	huhOptions := make([]huh.Option[int], len(labels))
+	for i, label := range labels {
+		huhOptions[i] = huh.NewOption(label, i)
+	}
+
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[int]().
+			Title(title).
+			Options(huhOptions...).
+			Height(15).
+			Filtering(true).
+			Value(&selected),
+	))
+
+	if err := form.Run(); err != nil {
+		return -1, err
+	}
+	return selected, nil
+}
+
+func RenderMultiSelect(title string, labels []string) ([]int, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	var selected []int
+
+	huhOptions := make([]huh.Option[int], len(labels))
+	for i, label := range labels {
+		huhOptions[i] = huh.NewOption(label, i)
+	}
+
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewMultiSelect[int]().
+			Title(title).
+			Options(huhOptions...).
+			Height(15).
+			Filterable(true).
+			Value(&selected),
+	))
+
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
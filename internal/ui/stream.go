@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// streamPreviewLines is how many trailing lines of the partial response are
+// shown under the spinner.
+const streamPreviewLines = 6
+
+type streamProgressMsg struct {
+	text      string
+	tokens    int
+	estimated bool
+}
+
+type streamDoneMsg struct{}
+
+type streamSpinner struct {
+	spinner spinner.Model
+	title   string
+	start   time.Time
+	text    string
+	tokens  int
+	// estimated is set while tokens is a guess from the partial text.
+	estimated bool
+	done      bool
+}
+
+func (s *streamSpinner) Init() tea.Cmd {
+	return s.spinner.Tick
+}
+
+func (s *streamSpinner) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case streamProgressMsg:
+		s.text = msg.text
+		s.tokens = msg.tokens
+		s.estimated = msg.estimated
+		return s, nil
+	case streamDoneMsg:
+		s.done = true
+		return s, tea.Quit
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return s, tea.Interrupt
+		}
+	}
+
+	var cmd tea.Cmd
+	s.spinner, cmd = s.spinner.Update(msg)
+	return s, cmd
+}
+
+func (s *streamSpinner) View() string {
+	if s.done {
+		return ""
+	}
+
+	tokens := fmt.Sprintf("%d tokens", s.tokens)
+	if s.estimated {
+		tokens = "~" + tokens
+	}
+	status := lipgloss.NewStyle().Faint(true).Render(
+		fmt.Sprintf("(%.1fs, %s)", time.Since(s.start).Seconds(), tokens),
+	)
+	view := fmt.Sprintf("%s%s %s", s.spinner.View(), s.title, status)
+
+	if text := strings.TrimSpace(s.text); text != "" {
+		lines := strings.Split(text, "\n")
+		if len(lines) > streamPreviewLines {
+			lines = lines[len(lines)-streamPreviewLines:]
+		}
+		preview := lipgloss.NewStyle().Faint(true).MaxWidth(100).Render(strings.Join(lines, "\n"))
+		view += "\n" + preview
+	}
+
+	return view
+}
+
+// RenderStreamSpinner works like RenderSpinner, but action gets a progress
+// callback and the partial response, elapsed time and token count are shown
+// live until it returns. Estimated counts are marked with a ~.
+func RenderStreamSpinner(title string, action func(progress func(text string, tokens int, estimated bool))) error {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#F780E2"))
+
+	p := tea.NewProgram(&streamSpinner{
+		spinner: s,
+		title:   title,
+		start:   time.Now(),
+	}, tea.WithOutput(os.Stderr))
+
+	go func() {
+		action(func(text string, tokens int, estimated bool) {
+			p.Send(streamProgressMsg{text: text, tokens: tokens, estimated: estimated})
+		})
+		p.Send(streamDoneMsg{})
+	}()
+
+	_, err := p.Run()
+	return err
+}
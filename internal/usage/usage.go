@@ -0,0 +1,199 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/model"
+	"github.com/Beriholic/geminic/internal/xdg"
+)
+
+const (
+	KindCommit  string = "commit"
+	KindExplain string = "explain"
+	KindReview  string = "review"
+)
+
+const (
+	OutcomeConfirmed string = "confirmed"
+	OutcomeEdited    string = "edited"
+	OutcomeRolled    string = "rolled"
+	OutcomeCancelled string = "cancelled"
+	// OutcomeFailed is a message the user accepted but git did not commit,
+	// e.g. because a hook rejected it.
+	OutcomeFailed string = "failed"
+)
+
+// Record is one line of the usage log: a single model response and, for
+// commit messages, what the user did with it.
+type Record struct {
+	Time             time.Time `json:"time"`
+	Repo             string    `json:"repo"`
+	Kind             string    `json:"kind"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	Cached           bool      `json:"cached,omitempty"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Outcome          string    `json:"outcome,omitempty"`
+}
+
+func logPath() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
+
+func Append(record Record) error {
+	path, err := logPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage log: %v", err)
+	}
+	return nil
+}
+
+// Load reads every record since the given time. Lines that fail to parse are
+// skipped so one corrupt write does not hide the rest of the log.
+func Load(since time.Time) ([]Record, error) {
+	path, err := logPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage log: %v", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.Time.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %v", err)
+	}
+	return records, nil
+}
+
+type Summary struct {
+	Key              string
+	Requests         int
+	Cached           int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+	// UnpricedRequests counts requests to models missing from the price table,
+	// which are left out of Cost.
+	UnpricedRequests int
+	Confirmed        int
+	Edited           int
+	Rolled           int
+	Cancelled        int
+	Failed           int
+}
+
+// AcceptanceRate is the share of generated commit messages that ended up
+// committed, edited or not, or -1 when none was generated.
+func (s *Summary) AcceptanceRate() float64 {
+	total := s.Confirmed + s.Edited + s.Rolled + s.Cancelled + s.Failed
+	if total == 0 {
+		return -1
+	}
+	return float64(s.Confirmed+s.Edited) / float64(total)
+}
+
+// Summarize groups records by the key function, sorted by key.
+func Summarize(records []Record, prices []model.Price, key func(Record) string) []*Summary {
+	groups := make(map[string]*Summary)
+	for _, record := range records {
+		k := key(record)
+		summary, ok := groups[k]
+		if !ok {
+			summary = &Summary{Key: k}
+			groups[k] = summary
+		}
+
+		summary.Requests++
+		summary.PromptTokens += record.PromptTokens
+		summary.CompletionTokens += record.CompletionTokens
+		if record.Cached {
+			summary.Cached++
+		} else if price, ok := findPrice(prices, record.Model); ok {
+			summary.Cost += float64(record.PromptTokens)/1e6*price.Input +
+				float64(record.CompletionTokens)/1e6*price.Output
+		} else {
+			summary.UnpricedRequests++
+		}
+
+		switch record.Outcome {
+		case OutcomeConfirmed:
+			summary.Confirmed++
+		case OutcomeEdited:
+			summary.Edited++
+		case OutcomeRolled:
+			summary.Rolled++
+		case OutcomeCancelled:
+			summary.Cancelled++
+		case OutcomeFailed:
+			summary.Failed++
+		}
+	}
+
+	summaries := make([]*Summary, 0, len(groups))
+	for _, summary := range groups {
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Key < summaries[j].Key
+	})
+	return summaries
+}
+
+func findPrice(prices []model.Price, name string) (model.Price, bool) {
+	name = strings.TrimPrefix(name, "models/")
+	for _, price := range prices {
+		if strings.TrimPrefix(price.Model, "models/") == name {
+			return price, true
+		}
+	}
+	return model.Price{}, false
+}
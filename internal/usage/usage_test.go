@@ -0,0 +1,109 @@
+package usage
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Beriholic/geminic/internal/model"
+)
+
+func TestSummarize(t *testing.T) {
+	prices := []model.Price{
+		{Model: "gemini-2.0-flash", Input: 0.1, Output: 0.4},
+	}
+	records := []Record{
+		{Repo: "/a", Model: "models/gemini-2.0-flash", PromptTokens: 1_000_000, CompletionTokens: 500_000, Outcome: OutcomeConfirmed},
+		{Repo: "/a", Model: "gemini-2.0-flash", PromptTokens: 1_000_000, Cached: true, Outcome: OutcomeEdited},
+		{Repo: "/a", Model: "gpt", PromptTokens: 10, Outcome: OutcomeRolled},
+		{Repo: "/b", Model: "gemini-2.0-flash", Outcome: OutcomeFailed},
+		{Repo: "/b", Model: "gemini-2.0-flash", Outcome: OutcomeCancelled},
+		{Repo: "/b", Model: "gemini-2.0-flash", Kind: KindExplain},
+	}
+
+	summaries := Summarize(records, prices, func(r Record) string { return r.Repo })
+	if len(summaries) != 2 {
+		t.Fatalf("Summarize() returned %d groups, want 2", len(summaries))
+	}
+
+	a, b := summaries[0], summaries[1]
+	tests := []struct {
+		name      string
+		got, want any
+	}{
+		{"sorted by key", a.Key + b.Key, "/a/b"},
+		{"requests", a.Requests, 3},
+		{"cached", a.Cached, 1},
+		{"prompt tokens", a.PromptTokens, 2_000_010},
+		{"unpriced", a.UnpricedRequests, 1},
+		{"confirmed", a.Confirmed, 1},
+		{"edited", a.Edited, 1},
+		{"rolled", a.Rolled, 1},
+		{"failed", b.Failed, 1},
+		{"cancelled", b.Cancelled, 1},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// Cached responses cost nothing.
+	if want := 0.1 + 0.5*0.4; math.Abs(a.Cost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", a.Cost, want)
+	}
+}
+
+func TestAcceptanceRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary Summary
+		want    float64
+	}{
+		{"nothing generated", Summary{}, -1},
+		{"all accepted", Summary{Confirmed: 1, Edited: 1}, 1},
+		{"failed commits are not accepted", Summary{Confirmed: 1, Failed: 1}, 0.5},
+		{"mixed", Summary{Confirmed: 1, Rolled: 2, Cancelled: 1}, 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.summary.AcceptanceRate(); got != tt.want {
+				t.Errorf("AcceptanceRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	now := time.Now()
+	for _, record := range []Record{
+		{Time: now.Add(-48 * time.Hour), Model: "old"},
+		{Time: now, Model: "new"},
+	} {
+		if err := Append(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, err := logPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("not json\n")
+	file.Close()
+
+	records, err := Load(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Model != "new" {
+		t.Errorf("Load() = %+v, want only the new record", records)
+	}
+}
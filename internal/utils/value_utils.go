@@ -6,3 +6,11 @@ func GetStrngOrDefault(value string, defaultValue string) string {
 	}
 	return value
 }
+
+func GetOrDefault[T comparable](value T, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ConfigDir returns geminic's config directory: $XDG_CONFIG_HOME, falling
+// back to ~/.config on every platform, where geminic has always kept it.
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "geminic"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "geminic"), nil
+}
+
+// DataDir returns geminic's directory for persistent data: $XDG_DATA_HOME,
+// falling back to ~/.local/share on Unix and the platform's application data
+// directory elsewhere.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "geminic"), nil
+	}
+
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find data directory: %v", err)
+		}
+		return filepath.Join(dir, "geminic"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find data directory: %v", err)
+	}
+	return filepath.Join(home, ".local", "share", "geminic"), nil
+}
+
+// CacheDir returns geminic's cache directory, which honours $XDG_CACHE_HOME
+// on Unix.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find cache directory: %v", err)
+	}
+	return filepath.Join(dir, "geminic"), nil
+}